@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 
@@ -59,8 +60,14 @@ func main() {
 			continue
 		}
 
-		// Parse result
-		resultMap := result.(map[string]interface{})
-		fmt.Printf("%g %s %g = %g\n", calc.a, calc.operation, calc.b, resultMap["result"])
+		// Parse result, which the server returns as JSON text
+		var value struct {
+			Result float64 `json:"result"`
+		}
+		if json.Unmarshal([]byte(result.Text()), &value) != nil {
+			log.Printf("Unexpected result for %s: %+v", calc.operation, result)
+			continue
+		}
+		fmt.Printf("%g %s %g = %g\n", calc.a, calc.operation, calc.b, value.Result)
 	}
 }
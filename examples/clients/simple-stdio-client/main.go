@@ -74,5 +74,5 @@ func main() {
 	}
 
 	fmt.Println("\nEcho result:")
-	fmt.Printf("%+v\n", result)
+	fmt.Println(result.Text())
 }
@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"time"
 
@@ -14,13 +13,12 @@ import (
 	mcpLogger "github.com/harriteja/mcp-go-sdk/pkg/logger"
 	"github.com/harriteja/mcp-go-sdk/pkg/server"
 	transportfiber "github.com/harriteja/mcp-go-sdk/pkg/server/transport/fiber"
-	"github.com/harriteja/mcp-go-sdk/pkg/types"
 )
 
 // SumRequest is a request to the sum tool
 type SumRequest struct {
-	A int `json:"a"`
-	B int `json:"b"`
+	A int `json:"a" jsonschema:"description=First number"`
+	B int `json:"b" jsonschema:"description=Second number"`
 }
 
 // SumResponse is a response from the sum tool
@@ -64,51 +62,14 @@ func main() {
 		log.Fatalf("Failed to create MCP server: %v", err)
 	}
 
-	// Register MCP tools
-	mcpServer.OnListTools(func(ctx context.Context) ([]types.Tool, error) {
-		return []types.Tool{
-			{
-				Name:        "sum",
-				Description: "Add two numbers",
-				Parameters: &types.Parameters{
-					Type: "object",
-					Properties: map[string]types.Parameter{
-						"a": {
-							Type:        "number",
-							Description: "First number",
-						},
-						"b": {
-							Type:        "number",
-							Description: "Second number",
-						},
-					},
-				},
-			},
-		}, nil
-	})
-
-	mcpServer.OnCallTool(func(ctx context.Context, name string, args map[string]interface{}) (interface{}, error) {
-		switch name {
-		case "sum":
-			// Parse arguments
-			a, ok := args["a"].(float64)
-			if !ok {
-				return nil, fmt.Errorf("invalid argument 'a'")
-			}
-			b, ok := args["b"].(float64)
-			if !ok {
-				return nil, fmt.Errorf("invalid argument 'b'")
-			}
-
-			// Perform calculation
-			result := int(a) + int(b)
-
-			// Return response
-			return SumResponse{Result: result}, nil
-		default:
-			return nil, fmt.Errorf("unknown tool: %s", name)
-		}
-	})
+	// Register MCP tools. The input schema is generated from SumRequest.
+	err = mcpServer.AddTool(server.NewTypedTool("sum", "Add two numbers",
+		func(ctx context.Context, in SumRequest) (SumResponse, error) {
+			return SumResponse{Result: in.A + in.B}, nil
+		}))
+	if err != nil {
+		log.Fatalf("Failed to add tool: %v", err)
+	}
 
 	// Create a Fiber adapter for the MCP server
 	fiberAdapter := transportfiber.New(mcpServer, mcpLogger.GetDefaultLogger())
@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"net/http"
 	"time"
@@ -12,13 +11,12 @@ import (
 	mcpLogger "github.com/harriteja/mcp-go-sdk/pkg/logger"
 	"github.com/harriteja/mcp-go-sdk/pkg/server"
 	transportgin "github.com/harriteja/mcp-go-sdk/pkg/server/transport/gin"
-	"github.com/harriteja/mcp-go-sdk/pkg/types"
 )
 
 // MultiplyRequest is a request to the multiply tool
 type MultiplyRequest struct {
-	A float64 `json:"a"`
-	B float64 `json:"b"`
+	A float64 `json:"a" jsonschema:"description=First number"`
+	B float64 `json:"b" jsonschema:"description=Second number"`
 }
 
 // MultiplyResponse is a response from the multiply tool
@@ -93,51 +91,14 @@ func main() {
 		log.Fatalf("Failed to create MCP server: %v", err)
 	}
 
-	// Register MCP tools
-	mcpServer.OnListTools(func(ctx context.Context) ([]types.Tool, error) {
-		return []types.Tool{
-			{
-				Name:        "multiply",
-				Description: "Multiply two numbers",
-				Parameters: &types.Parameters{
-					Type: "object",
-					Properties: map[string]types.Parameter{
-						"a": {
-							Type:        "number",
-							Description: "First number",
-						},
-						"b": {
-							Type:        "number",
-							Description: "Second number",
-						},
-					},
-				},
-			},
-		}, nil
-	})
-
-	mcpServer.OnCallTool(func(ctx context.Context, name string, args map[string]interface{}) (interface{}, error) {
-		switch name {
-		case "multiply":
-			// Parse arguments
-			a, ok := args["a"].(float64)
-			if !ok {
-				return nil, fmt.Errorf("invalid argument 'a'")
-			}
-			b, ok := args["b"].(float64)
-			if !ok {
-				return nil, fmt.Errorf("invalid argument 'b'")
-			}
-
-			// Perform calculation
-			result := a * b
-
-			// Return response
-			return MultiplyResponse{Result: result}, nil
-		default:
-			return nil, fmt.Errorf("unknown tool: %s", name)
-		}
-	})
+	// Register MCP tools. The input schema is generated from MultiplyRequest.
+	err = mcpServer.AddTool(server.NewTypedTool("multiply", "Multiply two numbers",
+		func(ctx context.Context, in MultiplyRequest) (MultiplyResponse, error) {
+			return MultiplyResponse{Result: in.A * in.B}, nil
+		}))
+	if err != nil {
+		log.Fatalf("Failed to add tool: %v", err)
+	}
 
 	// Create a Gin adapter for the MCP server
 	ginAdapter := transportgin.New(mcpServer, mcpLogger.GetDefaultLogger())
@@ -2,14 +2,23 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 
 	"github.com/harriteja/mcp-go-sdk/pkg/logger"
 	"github.com/harriteja/mcp-go-sdk/pkg/server"
 	"github.com/harriteja/mcp-go-sdk/pkg/server/transport/stdio"
-	"github.com/harriteja/mcp-go-sdk/pkg/types"
 )
 
+// EchoInput is the input of the echo tool
+type EchoInput struct {
+	Message string `json:"message" jsonschema:"description=Message to echo back"`
+}
+
+// EchoOutput is the output of the echo tool
+type EchoOutput struct {
+	Message string `json:"message"`
+	Echo    bool   `json:"echo"`
+}
+
 func main() {
 	// Create a default logger
 	log := logger.New("simple-stdio")
@@ -24,48 +33,14 @@ func main() {
 		log.Panic(context.Background(), "main", "init", "Failed to create server: "+err.Error())
 	}
 
-	// Register tool handlers
-	srv.OnListTools(func(ctx context.Context) ([]types.Tool, error) {
-		schema := json.RawMessage(`{
-			"type": "object",
-			"properties": {
-				"message": {
-					"type": "string"
-				}
-			},
-			"required": ["message"]
-		}`)
-
-		return []types.Tool{
-			{
-				Name:        "echo",
-				Description: "Echo back the input message",
-				InputSchema: schema,
-			},
-		}, nil
-	})
-
-	srv.OnCallTool(func(ctx context.Context, name string, args map[string]interface{}) (interface{}, error) {
-		if name != "echo" {
-			return nil, &types.Error{
-				Code:    404,
-				Message: "Tool not found",
-			}
-		}
-
-		message, ok := args["message"].(string)
-		if !ok {
-			return nil, &types.Error{
-				Code:    400,
-				Message: "Invalid message parameter",
-			}
-		}
-
-		return map[string]interface{}{
-			"message": message,
-			"echo":    true,
-		}, nil
-	})
+	// Register the echo tool. Its input schema is generated from EchoInput.
+	err = srv.AddTool(server.NewTypedTool("echo", "Echo back the input message",
+		func(ctx context.Context, in EchoInput) (EchoOutput, error) {
+			return EchoOutput{Message: in.Message, Echo: true}, nil
+		}))
+	if err != nil {
+		log.Panic(context.Background(), "main", "init", "Failed to add tool: "+err.Error())
+	}
 
 	// Create stdio transport
 	transport := stdio.New(srv, stdio.Options{
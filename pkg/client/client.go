@@ -8,10 +8,14 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
 
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
+	"github.com/harriteja/mcp-go-sdk/pkg/keepalive"
 	"github.com/harriteja/mcp-go-sdk/pkg/types"
 )
 
@@ -31,8 +35,41 @@ type Options struct {
 
 	// HTTPClient is the HTTP client to use (for HTTP transport)
 	HTTPClient *http.Client
+
+	// ProtocolVersions lists the protocol revisions the client speaks, the
+	// first one being requested during initialization. Defaults to
+	// types.SupportedProtocolVersions.
+	ProtocolVersions []string
+
+	// SamplingHandler answers the sampling/createMessage requests of the
+	// server. The client declares the sampling capability when it is set.
+	SamplingHandler SamplingHandler
+
+	// ElicitationHandler answers the elicitation/create requests of the
+	// server. The client declares the elicitation capability when it is set.
+	ElicitationHandler ElicitationHandler
+
+	// Roots provides the roots answered to roots/list requests, see
+	// StaticRoots for a fixed list. The client declares the roots
+	// capability when it is set.
+	Roots RootsProvider
+
+	// KeepAlive pings the server at an interval once initialized. When the
+	// server stops answering the connection is torn down: pending stdio
+	// requests fail and OnConnectionLost is called. Disabled by default.
+	KeepAlive keepalive.Options
+
+	// OnConnectionLost is called when keepalive finds the server dead
+	OnConnectionLost func(err error)
 }
 
+// headerSessionID carries the session ID on the HTTP transport
+const headerSessionID = "Mcp-Session-Id"
+
+// cancelTimeout bounds how long sending notifications/cancelled may take
+// after the caller's context is done
+const cancelTimeout = 5 * time.Second
+
 // Client represents an MCP client
 type Client struct {
 	serverURL    string
@@ -40,10 +77,73 @@ type Client struct {
 	httpClient   *http.Client
 	serverInfo   *types.Implementation
 	capabilities *types.ServerCapabilities
+	// declared holds the capabilities the client declared during
+	// initialization
+	declared types.ClientCapabilities
+
+	// protocolVersions lists the protocol revisions the client speaks, and
+	// protocolVersion is the one negotiated with the server
+	protocolVersions []string
+	protocolVersion  string
+
+	// nextID generates request IDs
+	nextID int64
+
+	// sessionID is the session assigned by an HTTP server during
+	// initialization
+	sessionMu sync.RWMutex
+	sessionID string
 
 	// stdio transport
-	reader io.Reader
-	writer io.Writer
+	reader  io.Reader
+	writer  io.Writer
+	decoder *json.Decoder
+	writeMu sync.Mutex
+
+	// pending holds the response channels of stdio requests awaiting an
+	// answer, keyed by request ID. readErr is set once the read loop stops.
+	pendingMu sync.Mutex
+	pending   map[string]chan *jsonrpc.Response
+	readErr   error
+
+	// outputSchemas validates tool results
+	outputSchemas outputSchemas
+
+	// notificationHandlers holds the handlers of server notifications,
+	// keyed by method
+	handlersMu           sync.RWMutex
+	notificationHandlers map[string]NotificationHandler
+
+	// progressHandlers holds the progress handlers of the calls in flight,
+	// keyed by progress token
+	progressMu        sync.Mutex
+	progressHandlers  map[string]ProgressHandler
+	nextProgressToken int64
+
+	// subscriptions holds the handlers of resource subscriptions, keyed by
+	// URI
+	subscriptionsMu sync.Mutex
+	subscriptions   map[string]ResourceUpdateHandler
+
+	// samplingHandler and elicitationHandler answer the requests of the
+	// server, and incoming holds the cancel functions of the server requests
+	// being answered, keyed by request ID
+	samplingHandler    SamplingHandler
+	elicitationHandler ElicitationHandler
+	incomingMu         sync.Mutex
+	incoming           map[string]context.CancelFunc
+
+	// roots provides the roots of the client
+	rootsMu sync.Mutex
+	roots   RootsProvider
+
+	// keeper pings the server once initialized, until stopKeepAlive is
+	// called. It is built once, so its metric is registered once however
+	// often the client initializes; keeperErr holds the error building it.
+	keeper           *keepalive.Keeper
+	keeperErr        error
+	onConnectionLost func(err error)
+	stopKeepAlive    context.CancelFunc
 }
 
 // New creates a new MCP client
@@ -53,243 +153,248 @@ func New(opts Options) *Client {
 		httpClient = http.DefaultClient
 	}
 
-	return &Client{
-		serverURL:  opts.ServerURL,
-		clientInfo: opts.ClientInfo,
-		httpClient: httpClient,
-		reader:     opts.Reader,
-		writer:     opts.Writer,
+	protocolVersions := opts.ProtocolVersions
+	if len(protocolVersions) == 0 {
+		protocolVersions = types.SupportedProtocolVersions
 	}
+
+	c := &Client{
+		serverURL:          opts.ServerURL,
+		clientInfo:         opts.ClientInfo,
+		httpClient:         httpClient,
+		protocolVersions:   protocolVersions,
+		reader:             opts.Reader,
+		writer:             opts.Writer,
+		samplingHandler:    opts.SamplingHandler,
+		elicitationHandler: opts.ElicitationHandler,
+		roots:              opts.Roots,
+		onConnectionLost:   opts.OnConnectionLost,
+	}
+	c.keeper, c.keeperErr = keepalive.New(opts.KeepAlive)
+	if opts.Reader != nil && opts.Writer != nil {
+		c.decoder = json.NewDecoder(opts.Reader)
+		c.pending = make(map[string]chan *jsonrpc.Response)
+		go c.readLoop()
+	}
+	return c
 }
 
-// Initialize initializes the client with the server
+// Initialize initializes the client with the server and completes the
+// handshake with notifications/initialized. It fails when the server answers
+// with a protocol version the client does not speak.
 func (c *Client) Initialize(ctx context.Context) error {
 	req := types.InitializeRequest{
-		ProtocolVersion: "1.0",
+		ProtocolVersion: c.protocolVersions[0],
 		ClientInfo:      c.clientInfo,
-		Capabilities:    types.ClientCapabilities{},
+		Capabilities:    c.clientCapabilities(),
 	}
 
+	// Initializing again, say after the server expired the session, starts
+	// a new session
+	c.sessionMu.Lock()
+	c.sessionID = ""
+	c.sessionMu.Unlock()
+
 	var resp types.InitializeResponse
-	if err := c.call(ctx, "initialize", req, &resp); err != nil {
+	if err := c.call(ctx, types.MethodInitialize, req, &resp); err != nil {
 		return errors.Wrap(err, "failed to initialize")
 	}
+	if !types.IsSupportedProtocolVersion(resp.ProtocolVersion, c.protocolVersions) {
+		return errors.Errorf("failed to initialize: unsupported protocol version %q", resp.ProtocolVersion)
+	}
 
+	c.protocolVersion = resp.ProtocolVersion
 	c.serverInfo = &resp.ServerInfo
 	c.capabilities = &resp.Capabilities
-	return nil
+	c.declared = req.Capabilities
+	if err := c.Initialized(ctx); err != nil {
+		return err
+	}
+	return c.startKeepAlive()
+}
+
+// ProtocolVersion returns the protocol version negotiated during
+// initialization
+func (c *Client) ProtocolVersion() string {
+	return c.protocolVersion
+}
+
+// ServerCapabilities returns the capabilities the server advertised during
+// initialization, or nil before it
+func (c *Client) ServerCapabilities() *types.ServerCapabilities {
+	return c.capabilities
 }
 
-// Initialized notifies the server that the client has completed initialization
+// Initialized notifies the server that the client has completed
+// initialization. Initialize already sends it.
 func (c *Client) Initialized(ctx context.Context) error {
 	// The initialized notification has an empty payload
 	notification := types.InitializedNotification{}
 
 	// This is a notification, not a request, so we don't expect a response
-	if err := c.call(ctx, "initialized", notification, nil); err != nil {
+	if err := c.notify(ctx, types.NotificationInitialized, notification); err != nil {
 		return errors.Wrap(err, "failed to send initialized notification")
 	}
 
 	return nil
 }
 
-// Ping sends a ping request to test server connectivity
-func (c *Client) Ping(ctx context.Context) (*types.PingResponse, error) {
-	req := types.PingRequest{
-		Timestamp: time.Now().UnixNano() / int64(time.Millisecond),
+// Ping checks that the server is alive and returns the round-trip time
+func (c *Client) Ping(ctx context.Context) (time.Duration, error) {
+	start := time.Now()
+	if err := c.call(ctx, types.MethodPing, nil, nil); err != nil {
+		return 0, errors.Wrap(err, "failed to ping server")
 	}
-
-	var resp types.PingResponse
-	if err := c.call(ctx, "ping", req, &resp); err != nil {
-		return nil, errors.Wrap(err, "failed to ping server")
-	}
-
-	return &resp, nil
+	return time.Since(start), nil
 }
 
-// Cancel sends a request to cancel an ongoing operation
-func (c *Client) Cancel(ctx context.Context, requestID string) error {
-	req := types.CancelRequest{
-		ID: requestID,
+// Cancel notifies the server that the request with the given ID is no longer
+// needed
+func (c *Client) Cancel(ctx context.Context, requestID jsonrpc.ID, reason string) error {
+	notification := types.CancelledNotification{
+		RequestID: requestID,
+		Reason:    reason,
 	}
 
 	// This is a notification, not a request, so we don't expect a response
-	if err := c.call(ctx, "cancel", req, nil); err != nil {
+	if err := c.notify(ctx, types.NotificationCancelled, notification); err != nil {
 		return errors.Wrap(err, "failed to send cancellation request")
 	}
 
 	return nil
 }
 
-// ListTools lists available tools from the server
+// ListTools lists available tools from the server, walking every page. The
+// output schemas of the tools are kept to validate the results of CallTool.
 func (c *Client) ListTools(ctx context.Context) ([]types.Tool, error) {
-	var tools []types.Tool
-	if err := c.call(ctx, "listTools", nil, &tools); err != nil {
-		return nil, errors.Wrap(err, "failed to list tools")
-	}
-	return tools, nil
+	return collect(c.ToolsIter(ctx))
 }
 
-// CallTool calls a tool on the server
-func (c *Client) CallTool(ctx context.Context, name string, args map[string]interface{}) (interface{}, error) {
-	req := struct {
-		Name string                 `json:"name"`
-		Args map[string]interface{} `json:"args"`
-	}{
-		Name: name,
-		Args: args,
+// CallTool calls a tool on the server. When the tool was listed with an
+// output schema, its structured content is validated against it. Pass
+// OnProgress to receive the progress the tool reports.
+func (c *Client) CallTool(ctx context.Context, name string, args map[string]interface{}, opts ...CallOption) (*types.CallToolResult, error) {
+	meta, done := c.trackProgress(applyCallOptions(opts).onProgress)
+	defer done()
+
+	req := types.CallToolRequest{
+		Name:      name,
+		Arguments: args,
+		Meta:      meta,
 	}
 
-	var result interface{}
-	if err := c.call(ctx, "callTool", req, &result); err != nil {
+	var result types.CallToolResult
+	if err := c.call(ctx, types.MethodToolsCall, req, &result); err != nil {
 		return nil, errors.Wrap(err, "failed to call tool")
 	}
-	return result, nil
+	if err := c.outputSchemas.check(name, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
 }
 
-// ListPrompts lists available prompts from the server
+// ListPrompts lists available prompts from the server, walking every page
 func (c *Client) ListPrompts(ctx context.Context) ([]types.Prompt, error) {
-	var prompts []types.Prompt
-	if err := c.call(ctx, "listPrompts", nil, &prompts); err != nil {
-		return nil, errors.Wrap(err, "failed to list prompts")
-	}
-	return prompts, nil
+	return collect(c.PromptsIter(ctx))
 }
 
-// GetPrompt gets a prompt from the server
-func (c *Client) GetPrompt(ctx context.Context, name string, args map[string]interface{}) (*types.Prompt, error) {
-	req := struct {
-		Name string                 `json:"name"`
-		Args map[string]interface{} `json:"args"`
-	}{
-		Name: name,
-		Args: args,
+// GetPrompt gets a prompt from the server, rendered with args into the
+// messages of a conversation
+func (c *Client) GetPrompt(ctx context.Context, name string, args map[string]interface{}) (*types.GetPromptResult, error) {
+	req := types.GetPromptRequest{
+		Name:      name,
+		Arguments: args,
 	}
 
-	var prompt types.Prompt
-	if err := c.call(ctx, "getPrompt", req, &prompt); err != nil {
+	var result types.GetPromptResult
+	if err := c.call(ctx, types.MethodPromptsGet, req, &result); err != nil {
 		return nil, errors.Wrap(err, "failed to get prompt")
 	}
-	return &prompt, nil
+	return &result, nil
 }
 
-// ListResources lists available resources from the server
+// ListResources lists available resources from the server, walking every
+// page
 func (c *Client) ListResources(ctx context.Context) ([]types.Resource, error) {
-	var resources []types.Resource
-	if err := c.call(ctx, "listResources", nil, &resources); err != nil {
-		return nil, errors.Wrap(err, "failed to list resources")
-	}
-	return resources, nil
+	return collect(c.ResourcesIter(ctx))
 }
 
-// ReadResource reads a resource from the server
+// ReadResource reads a resource from the server and returns the data and MIME
+// type of its first contents entry
 func (c *Client) ReadResource(ctx context.Context, uri string) ([]byte, string, error) {
-	req := struct {
-		URI string `json:"uri"`
-	}{
+	req := types.ReadResourceRequest{
 		URI: uri,
 	}
 
-	if c.reader != nil && c.writer != nil {
-		// Use stdio transport
-		var result struct {
-			Data     []byte `json:"data"`
-			MimeType string `json:"mimeType"`
-		}
-		if err := c.call(ctx, "readResource", req, &result); err != nil {
-			return nil, "", err
-		}
-		return result.Data, result.MimeType, nil
+	var result types.ReadResourceResult
+	if err := c.call(ctx, types.MethodResourcesRead, req, &result); err != nil {
+		return nil, "", errors.Wrap(err, "failed to read resource")
 	}
-
-	// Use HTTP transport
-	httpReq, err := c.newRequest(ctx, "readResource", req)
-	if err != nil {
-		return nil, "", errors.Wrap(err, "failed to create request")
+	if len(result.Contents) == 0 {
+		return nil, "", errors.New("failed to read resource: no contents returned")
 	}
 
-	resp, err := c.httpClient.Do(httpReq)
+	contents := result.Contents[0]
+	data, err := contents.Bytes()
 	if err != nil {
-		return nil, "", errors.Wrap(err, "failed to send request")
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		var mcpErr types.Error
-		if err := json.NewDecoder(resp.Body).Decode(&mcpErr); err != nil {
-			return nil, "", errors.Wrap(err, "failed to decode error response")
-		}
-		return nil, "", &mcpErr
+		return nil, "", errors.Wrap(err, "failed to read resource")
 	}
-
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, "", errors.Wrap(err, "failed to read response body")
-	}
-
-	return data, resp.Header.Get("Content-Type"), nil
+	return data, contents.MimeType, nil
 }
 
-// ListResourceTemplates lists available resource templates from the server
+// ListResourceTemplates lists available resource templates from the
+// server, walking every page
 func (c *Client) ListResourceTemplates(ctx context.Context) ([]types.ResourceTemplate, error) {
-	var templates []types.ResourceTemplate
-	if err := c.call(ctx, "listResourceTemplates", nil, &templates); err != nil {
-		return nil, errors.Wrap(err, "failed to list resource templates")
-	}
-	return templates, nil
+	return collect(c.ResourceTemplatesIter(ctx))
 }
 
-// call makes an RPC call to the server
-func (c *Client) call(ctx context.Context, method string, params interface{}, result interface{}) error {
-	if c.reader != nil && c.writer != nil {
-		return c.callStdio(method, params, result)
+// Complete asks the server for values of a prompt argument or a resource
+// template variable, see types.PromptReference and types.ResourceReference,
+// that complete value. Arguments holds the values of the arguments already
+// resolved, if any.
+func (c *Client) Complete(ctx context.Context, ref types.CompletionReference, argument, value string, arguments map[string]string) (*types.Completion, error) {
+	req := types.CompleteRequest{
+		Ref:      ref,
+		Argument: types.CompletionArgument{Name: argument, Value: value},
 	}
-	return c.callHTTP(ctx, method, params, result)
-}
-
-// callStdio makes an RPC call using stdio transport
-func (c *Client) callStdio(method string, params interface{}, result interface{}) error {
-	// Write request
-	req := struct {
-		Method string      `json:"method"`
-		Params interface{} `json:"params,omitempty"`
-	}{
-		Method: method,
-		Params: params,
+	if len(arguments) > 0 {
+		req.Context = &types.CompletionContext{Arguments: arguments}
 	}
 
-	if err := json.NewEncoder(c.writer).Encode(req); err != nil {
-		return errors.Wrap(err, "failed to encode request")
+	var result types.CompleteResult
+	if err := c.call(ctx, types.MethodCompletionComplete, req, &result); err != nil {
+		return nil, errors.Wrap(err, "failed to complete "+argument)
 	}
+	return &result.Completion, nil
+}
 
-	// Read response
-	var rawResp json.RawMessage
-	if err := json.NewDecoder(c.reader).Decode(&rawResp); err != nil {
-		if err == io.EOF {
-			// If we get EOF during initialize, it's an error
-			if method == "initialize" {
-				return errors.Wrap(err, "connection closed during initialize")
-			}
-			// For other methods, EOF after writing request means no response
-			return nil
-		}
-		return errors.Wrap(err, "failed to decode response")
+// call makes an RPC call to the server and decodes the result into result.
+// When ctx is done before the response arrives the server is sent
+// notifications/cancelled and the context error is returned.
+func (c *Client) call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	id := jsonrpc.NewIntID(atomic.AddInt64(&c.nextID, 1))
+	req, err := jsonrpc.NewRequest(id, method, params)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode request")
 	}
 
-	// Try to decode as error response first
-	var errResp struct {
-		Error *types.Error `json:"error"`
+	var resp *jsonrpc.Response
+	if c.isStdio() {
+		resp, err = c.callStdio(ctx, req)
+	} else {
+		resp, err = c.callHTTP(ctx, req)
 	}
-	if err := json.Unmarshal(rawResp, &errResp); err == nil && errResp.Error != nil {
-		return errResp.Error
+	if err != nil {
+		if ctx.Err() != nil {
+			c.cancelRequest(ctx, req)
+			return ctx.Err()
+		}
+		return err
 	}
 
-	// If no error, decode as result
-	var resultResp struct {
-		Result json.RawMessage `json:"result"`
-	}
-	if err := json.Unmarshal(rawResp, &resultResp); err != nil {
-		return errors.Wrap(err, "failed to decode response")
+	if resp.Error != nil {
+		// Return the error as *types.Error to preserve type information
+		return types.ErrorFromJSONRPC(resp.Error)
 	}
 
 	// If no result expected, return
@@ -297,120 +402,247 @@ func (c *Client) callStdio(method string, params interface{}, result interface{}
 		return nil
 	}
 
-	// Decode result
-	if err := json.Unmarshal(resultResp.Result, result); err != nil {
+	if err := json.Unmarshal(resp.Result, result); err != nil {
 		return errors.Wrap(err, "failed to decode result")
 	}
-
 	return nil
 }
 
-// callHTTP makes an HTTP request and decodes the response
-func (c *Client) callHTTP(ctx context.Context, method string, params interface{}, result interface{}) error {
-	// Create request body
-	var body io.Reader
-	if params != nil {
-		data, err := json.Marshal(params)
-		if err != nil {
-			return errors.Wrap(err, "failed to marshal request body")
-		}
-		body = bytes.NewReader(data)
-	}
+// cancelRequest tells the server that the response to req is no longer needed
+func (c *Client) cancelRequest(ctx context.Context, req *jsonrpc.Request) {
+	reason := ctx.Err().Error()
 
-	// Ensure server URL has trailing slash
-	serverURL := strings.TrimSuffix(c.serverURL, "/") + "/"
+	// The caller's context is already done, but the notification must still go out
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cancelTimeout)
+	defer cancel()
 
-	// Create request URL
-	url := serverURL + method
-	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
-		url = "http://" + url
-	}
+	// Cancellation is best effort; the caller already has its error
+	_ = c.Cancel(ctx, req.ID, reason)
+}
 
-	// Create request
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+// notify sends a notification to the server. Notifications are never answered.
+func (c *Client) notify(ctx context.Context, method string, params interface{}) error {
+	notification, err := jsonrpc.NewNotification(method, params)
 	if err != nil {
-		return errors.Wrap(err, "failed to create request")
+		return errors.Wrap(err, "failed to encode notification")
 	}
 
-	// Set headers
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
+	if c.isStdio() {
+		return c.send(notification)
+	}
 
-	// Send request
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.post(ctx, notification)
 	if err != nil {
-		return errors.Wrap(err, "failed to send request")
+		return err
 	}
 	defer resp.Body.Close()
 
-	// Read response body
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return errors.Wrap(err, "failed to read response body")
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
+	return nil
+}
 
-	// Debug: Print response body
-	fmt.Printf("Response body: %s\n", string(respBody))
+// isStdio reports whether the client talks to the server over stdio
+func (c *Client) isStdio() bool {
+	return c.reader != nil && c.writer != nil
+}
+
+// send writes a single message to the stdio transport
+func (c *Client) send(v interface{}) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
 
-	// Try to decode as error response first
-	var errResp struct {
-		Error *types.Error `json:"error"`
+	if err := json.NewEncoder(c.writer).Encode(v); err != nil {
+		return errors.Wrap(err, "failed to encode message")
 	}
-	if err := json.Unmarshal(respBody, &errResp); err == nil && errResp.Error != nil {
-		// Return the error directly to preserve type information
-		return errResp.Error
+	return nil
+}
+
+// callStdio sends a request over the stdio transport and waits for the
+// response carrying the same ID
+func (c *Client) callStdio(ctx context.Context, req *jsonrpc.Request) (*jsonrpc.Response, error) {
+	key := req.ID.String()
+	ch := make(chan *jsonrpc.Response, 1)
+
+	c.pendingMu.Lock()
+	if c.readErr != nil {
+		err := c.readErr
+		c.pendingMu.Unlock()
+		return nil, errors.Wrap(err, "connection closed before response to "+req.Method)
 	}
+	c.pending[key] = ch
+	c.pendingMu.Unlock()
 
-	// If no error, decode as result
-	var resultResp struct {
-		Result json.RawMessage `json:"result"`
+	if err := c.send(req); err != nil {
+		c.removePending(key)
+		return nil, err
 	}
-	if err := json.Unmarshal(respBody, &resultResp); err != nil {
-		return errors.Wrap(err, "failed to decode response")
+
+	select {
+	case resp, ok := <-ch:
+		if !ok {
+			c.pendingMu.Lock()
+			defer c.pendingMu.Unlock()
+			return nil, errors.Wrap(c.readErr, "connection closed before response to "+req.Method)
+		}
+		return resp, nil
+	case <-ctx.Done():
+		c.removePending(key)
+		return nil, ctx.Err()
 	}
+}
 
-	// If result is nil, we don't need to decode further
-	if result == nil {
-		return nil
+// removePending forgets a request that no longer waits for its response
+func (c *Client) removePending(key string) {
+	c.pendingMu.Lock()
+	delete(c.pending, key)
+	c.pendingMu.Unlock()
+}
+
+// readLoop reads messages from the stdio transport and hands responses to
+// the requests waiting for them. When reading fails every pending request is
+// released.
+func (c *Client) readLoop() {
+	for {
+		var msg jsonrpc.Message
+		if err := c.decoder.Decode(&msg); err != nil {
+			if err != io.EOF {
+				err = errors.Wrap(err, "failed to decode message")
+			}
+			c.pendingMu.Lock()
+			if c.readErr == nil {
+				c.readErr = err
+			}
+			for key, ch := range c.pending {
+				close(ch)
+				delete(c.pending, key)
+			}
+			c.pendingMu.Unlock()
+			return
+		}
+
+		if msg.IsNotification() {
+			c.handleNotification(context.Background(), &msg)
+			continue
+		}
+		if msg.IsRequest() {
+			c.serveRequest(context.Background(), &msg)
+			continue
+		}
+
+		// Skip anything that is not a response, and responses nobody waits
+		// for anymore
+		if !msg.IsResponse() {
+			continue
+		}
+		key := msg.RequestID().String()
+		c.pendingMu.Lock()
+		ch, ok := c.pending[key]
+		delete(c.pending, key)
+		c.pendingMu.Unlock()
+		if ok {
+			ch <- msg.Response()
+		}
+	}
+}
+
+// callHTTP posts a request to the server and decodes the response
+func (c *Client) callHTTP(ctx context.Context, req *jsonrpc.Request) (*jsonrpc.Response, error) {
+	httpResp, err := c.post(ctx, req)
+	if err != nil {
+		return nil, err
 	}
+	defer httpResp.Body.Close()
 
-	// Decode result into target type
-	if err := json.Unmarshal(resultResp.Result, result); err != nil {
-		return errors.Wrap(err, "failed to decode response")
+	// The server streams the notifications of the request before the
+	// response when it sends any
+	if strings.HasPrefix(httpResp.Header.Get("Content-Type"), "text/event-stream") {
+		return c.readStreamedResponse(ctx, httpResp.Body, req)
 	}
 
-	return nil
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read response body")
+	}
+
+	var msg jsonrpc.Message
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode response (status %d)", httpResp.StatusCode)
+	}
+	if !msg.IsResponse() {
+		return nil, fmt.Errorf("unexpected response (status %d)", httpResp.StatusCode)
+	}
+	if msg.Error == nil && msg.RequestID().String() != req.ID.String() {
+		return nil, fmt.Errorf("response ID %s does not match request ID %s", msg.RequestID(), req.ID)
+	}
+	return msg.Response(), nil
 }
 
-// newRequest creates a new HTTP request
-func (c *Client) newRequest(ctx context.Context, method string, params interface{}) (*http.Request, error) {
-	body := struct {
-		Method string      `json:"method"`
-		Params interface{} `json:"params,omitempty"`
-	}{
-		Method: method,
-		Params: params,
+// url returns the server URL, defaulting to the http scheme
+func (c *Client) url() string {
+	if !strings.HasPrefix(c.serverURL, "http://") && !strings.HasPrefix(c.serverURL, "https://") {
+		return "http://" + c.serverURL
 	}
+	return c.serverURL
+}
 
-	bodyBytes, err := json.Marshal(body)
+// readStreamedResponse reads the events of a response stream, handling
+// notifications until the response to req arrives
+func (c *Client) readStreamedResponse(ctx context.Context, r io.Reader, req *jsonrpc.Request) (*jsonrpc.Response, error) {
+	var resp *jsonrpc.Response
+	err := readEvents(r, func(msg *jsonrpc.Message) bool {
+		switch {
+		case msg.IsNotification():
+			c.handleNotification(ctx, msg)
+		case msg.IsRequest():
+			c.serveRequest(ctx, msg)
+		case msg.IsResponse() && msg.RequestID().String() == req.ID.String():
+			resp = msg.Response()
+			return false
+		}
+		return true
+	})
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to marshal request body")
+		return nil, err
 	}
+	if resp == nil {
+		return nil, errors.New("event stream ended before response to " + req.Method)
+	}
+	return resp, nil
+}
 
-	// Ensure server URL has trailing slash
-	serverURL := strings.TrimSuffix(c.serverURL, "/") + "/"
-
-	// Create request URL
-	url := serverURL + method
-	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
-		url = "http://" + url
+// post sends a JSON-RPC message to the server URL
+func (c *Client) post(ctx context.Context, v interface{}) (*http.Response, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal request body")
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(bodyBytes))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url(), bytes.NewReader(data))
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create request")
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	return req, nil
+	req.Header.Set("Accept", "application/json, text/event-stream")
+
+	c.sessionMu.RLock()
+	if c.sessionID != "" {
+		req.Header.Set(headerSessionID, c.sessionID)
+	}
+	c.sessionMu.RUnlock()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to send request")
+	}
+
+	// The server assigns the session when answering initialize
+	if sessionID := resp.Header.Get(headerSessionID); sessionID != "" {
+		c.sessionMu.Lock()
+		c.sessionID = sessionID
+		c.sessionMu.Unlock()
+	}
+	return resp, nil
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
 	"github.com/harriteja/mcp-go-sdk/pkg/types"
 )
 
@@ -37,17 +38,25 @@ func TestClient_StdioTransport(t *testing.T) {
 
 		// Handle initialize
 		var req struct {
-			Method string                  `json:"method"`
-			Params types.InitializeRequest `json:"params"`
+			JSONRPC string                  `json:"jsonrpc"`
+			ID      jsonrpc.ID              `json:"id"`
+			Method  string                  `json:"method"`
+			Params  types.InitializeRequest `json:"params"`
 		}
 		if err := json.NewDecoder(clientToServerReader).Decode(&req); err != nil {
 			t.Errorf("Failed to decode initialize request: %v", err)
 			return
 		}
 
+		assert.Equal(t, jsonrpc.Version, req.JSONRPC)
+		assert.Equal(t, "initialize", req.Method)
 		resp := struct {
-			Result types.InitializeResponse `json:"result"`
+			JSONRPC string                   `json:"jsonrpc"`
+			ID      jsonrpc.ID               `json:"id"`
+			Result  types.InitializeResponse `json:"result"`
 		}{
+			JSONRPC: jsonrpc.Version,
+			ID:      req.ID,
 			Result: types.InitializeResponse{
 				ProtocolVersion: req.Params.ProtocolVersion,
 				ServerInfo: types.Implementation{
@@ -61,19 +70,34 @@ func TestClient_StdioTransport(t *testing.T) {
 			return
 		}
 
+		// Initialize completes the handshake
+		var notification jsonrpc.Message
+		if err := json.NewDecoder(clientToServerReader).Decode(&notification); err != nil {
+			t.Errorf("Failed to decode initialized notification: %v", err)
+			return
+		}
+		assert.Equal(t, types.NotificationInitialized, notification.Method)
+
 		// Handle list tools
 		if err := json.NewDecoder(clientToServerReader).Decode(&req); err != nil {
 			t.Errorf("Failed to decode list tools request: %v", err)
 			return
 		}
 
+		assert.Equal(t, types.MethodToolsList, req.Method)
 		toolsResp := struct {
-			Result []types.Tool `json:"result"`
+			JSONRPC string                `json:"jsonrpc"`
+			ID      jsonrpc.ID            `json:"id"`
+			Result  types.ListToolsResult `json:"result"`
 		}{
-			Result: []types.Tool{
-				{
-					Name:        "test-tool",
-					Description: "A test tool",
+			JSONRPC: jsonrpc.Version,
+			ID:      req.ID,
+			Result: types.ListToolsResult{
+				Tools: []types.Tool{
+					{
+						Name:        "test-tool",
+						Description: "A test tool",
+					},
 				},
 			},
 		}
@@ -84,22 +108,35 @@ func TestClient_StdioTransport(t *testing.T) {
 
 		// Handle call tool
 		var callReq struct {
-			Method string `json:"method"`
-			Params struct {
-				Name string                 `json:"name"`
-				Args map[string]interface{} `json:"args"`
-			} `json:"params"`
+			ID     jsonrpc.ID            `json:"id"`
+			Method string                `json:"method"`
+			Params types.CallToolRequest `json:"params"`
 		}
 		if err := json.NewDecoder(clientToServerReader).Decode(&callReq); err != nil {
 			t.Errorf("Failed to decode call tool request: %v", err)
 			return
 		}
+		assert.Equal(t, types.MethodToolsCall, callReq.Method)
+		assert.Equal(t, "value", callReq.Params.Arguments["arg"])
+
+		// Send a notification first; the client must skip it while waiting
+		if err := json.NewEncoder(serverToClientWriter).Encode(jsonrpc.Notification{
+			JSONRPC: jsonrpc.Version,
+			Method:  "progress",
+		}); err != nil {
+			t.Errorf("Failed to encode notification: %v", err)
+			return
+		}
 
 		callResp := struct {
-			Result map[string]interface{} `json:"result"`
+			JSONRPC string               `json:"jsonrpc"`
+			ID      jsonrpc.ID           `json:"id"`
+			Result  types.CallToolResult `json:"result"`
 		}{
-			Result: map[string]interface{}{
-				"result": "success",
+			JSONRPC: jsonrpc.Version,
+			ID:      callReq.ID,
+			Result: types.CallToolResult{
+				Content: []types.Content{types.NewTextContent("success")},
 			},
 		}
 		if err := json.NewEncoder(serverToClientWriter).Encode(callResp); err != nil {
@@ -124,7 +161,8 @@ func TestClient_StdioTransport(t *testing.T) {
 		"arg": "value",
 	})
 	require.NoError(t, err)
-	assert.Equal(t, "success", result.(map[string]interface{})["result"])
+	require.Len(t, result.Content, 1)
+	assert.Equal(t, types.NewTextContent("success"), result.Content[0])
 
 	// Clean up
 	clientToServerWriter.Close()
@@ -163,7 +201,8 @@ func TestClient_StdioError(t *testing.T) {
 
 		// Read request
 		var req struct {
-			Method string `json:"method"`
+			ID     jsonrpc.ID `json:"id"`
+			Method string     `json:"method"`
 		}
 		if err := json.NewDecoder(clientToServerReader).Decode(&req); err != nil {
 			t.Errorf("Failed to decode request: %v", err)
@@ -171,14 +210,7 @@ func TestClient_StdioError(t *testing.T) {
 		}
 
 		// Send error response
-		errResp := struct {
-			Error *types.Error `json:"error"`
-		}{
-			Error: &types.Error{
-				Code:    404,
-				Message: "Not found",
-			},
-		}
+		errResp := jsonrpc.NewErrorResponse(req.ID, jsonrpc.NewError(404, "Not found"))
 		if err := json.NewEncoder(serverToClientWriter).Encode(errResp); err != nil {
 			t.Errorf("Failed to encode error response: %v", err)
 			return
@@ -207,3 +239,148 @@ func TestClient_StdioError(t *testing.T) {
 		t.Fatal("Timeout waiting for server to complete")
 	}
 }
+
+func TestClient_ContextCancellation(t *testing.T) {
+	clientToServerReader, clientToServerWriter := io.Pipe()
+	serverToClientReader, serverToClientWriter := io.Pipe()
+
+	cli := New(Options{
+		Reader: serverToClientReader,
+		Writer: clientToServerWriter,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Mock server that never answers the tool call
+	received := make(chan jsonrpc.Message, 2)
+	go func() {
+		defer clientToServerReader.Close()
+		decoder := json.NewDecoder(clientToServerReader)
+		for i := 0; i < 2; i++ {
+			var msg jsonrpc.Message
+			if err := decoder.Decode(&msg); err != nil {
+				t.Errorf("Failed to decode message: %v", err)
+				return
+			}
+			received <- msg
+			if i == 0 {
+				cancel()
+			}
+		}
+	}()
+
+	_, err := cli.CallTool(ctx, "slow", nil)
+	require.ErrorIs(t, err, context.Canceled)
+
+	call := <-received
+	assert.Equal(t, types.MethodToolsCall, call.Method)
+
+	select {
+	case msg := <-received:
+		assert.Equal(t, types.NotificationCancelled, msg.Method)
+		var params types.CancelledNotification
+		require.Nil(t, msg.UnmarshalParams(&params))
+		assert.Equal(t, call.RequestID().String(), params.RequestID.String())
+		assert.NotEmpty(t, params.Reason)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timeout waiting for cancellation notification")
+	}
+
+	clientToServerWriter.Close()
+	serverToClientWriter.Close()
+}
+
+func TestClient_RejectsUnsupportedProtocolVersion(t *testing.T) {
+	clientToServerReader, clientToServerWriter := io.Pipe()
+	serverToClientReader, serverToClientWriter := io.Pipe()
+
+	cli := New(Options{
+		Reader: serverToClientReader,
+		Writer: clientToServerWriter,
+	})
+
+	go func() {
+		defer clientToServerReader.Close()
+		var req jsonrpc.Request
+		if err := json.NewDecoder(clientToServerReader).Decode(&req); err != nil {
+			t.Errorf("Failed to decode initialize request: %v", err)
+			return
+		}
+		assert.JSONEq(t, `{"protocolVersion":"`+types.LatestProtocolVersion+`","capabilities":{},"clientInfo":{"name":"","version":""}}`, string(req.Params))
+
+		resp, err := jsonrpc.NewResponse(req.ID, types.InitializeResponse{ProtocolVersion: "1.0"})
+		if err != nil {
+			t.Errorf("Failed to create response: %v", err)
+			return
+		}
+		if err := json.NewEncoder(serverToClientWriter).Encode(resp); err != nil {
+			t.Errorf("Failed to encode initialize response: %v", err)
+		}
+	}()
+
+	err := cli.Initialize(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unsupported protocol version "1.0"`)
+	assert.Empty(t, cli.ProtocolVersion())
+
+	clientToServerWriter.Close()
+	serverToClientWriter.Close()
+}
+
+func TestClient_SetRootsWithoutCapability(t *testing.T) {
+	clientToServerReader, clientToServerWriter := io.Pipe()
+	serverToClientReader, serverToClientWriter := io.Pipe()
+
+	// The client has no roots when it initializes, so it does not declare
+	// the roots capability
+	cli := New(Options{
+		Reader: serverToClientReader,
+		Writer: clientToServerWriter,
+	})
+
+	methods := make(chan string, 10)
+	go func() {
+		defer clientToServerReader.Close()
+		defer serverToClientWriter.Close()
+
+		decoder := json.NewDecoder(clientToServerReader)
+		encoder := json.NewEncoder(serverToClientWriter)
+		for {
+			var msg jsonrpc.Message
+			if err := decoder.Decode(&msg); err != nil {
+				return
+			}
+			methods <- msg.Method
+			if !msg.IsRequest() {
+				continue
+			}
+
+			var result interface{} = struct{}{}
+			if msg.Method == types.MethodInitialize {
+				result = types.InitializeResponse{ProtocolVersion: types.LatestProtocolVersion}
+			}
+			resp, err := jsonrpc.NewResponse(msg.RequestID(), result)
+			if err != nil {
+				t.Errorf("Failed to create response: %v", err)
+				return
+			}
+			if err := encoder.Encode(resp); err != nil {
+				t.Errorf("Failed to encode response: %v", err)
+				return
+			}
+		}
+	}()
+
+	require.NoError(t, cli.Initialize(context.Background()))
+	require.NoError(t, cli.SetRoots(context.Background(), types.Root{URI: "file:///project"}))
+	_, err := cli.Ping(context.Background())
+	require.NoError(t, err)
+
+	// The ping directly follows the handshake
+	assert.Equal(t, types.MethodInitialize, <-methods)
+	assert.Equal(t, types.NotificationInitialized, <-methods)
+	assert.Equal(t, types.MethodPing, <-methods)
+
+	clientToServerWriter.Close()
+	serverToClientWriter.Close()
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// startKeepAlive starts pinging the server if keepalive is enabled
+func (c *Client) startKeepAlive() error {
+	if c.keeperErr != nil {
+		return errors.Wrap(c.keeperErr, "failed to set up keepalive")
+	}
+	keeper := c.keeper
+	if !keeper.Enabled() {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.pendingMu.Lock()
+	if c.stopKeepAlive != nil {
+		c.stopKeepAlive()
+	}
+	c.stopKeepAlive = cancel
+	c.pendingMu.Unlock()
+
+	go keeper.Run(ctx, func(ctx context.Context) error {
+		_, err := c.Ping(ctx)
+		return err
+	}, c.connectionLost)
+	return nil
+}
+
+// Close stops the background work of the client, such as keepalive pings
+func (c *Client) Close() error {
+	c.pendingMu.Lock()
+	defer c.pendingMu.Unlock()
+	if c.stopKeepAlive != nil {
+		c.stopKeepAlive()
+		c.stopKeepAlive = nil
+	}
+	return nil
+}
+
+// connectionLost tears down the connection to a server that stopped
+// answering. Pending stdio requests fail with err, as do later ones.
+func (c *Client) connectionLost(err error) {
+	if c.isStdio() {
+		c.pendingMu.Lock()
+		if c.readErr == nil {
+			c.readErr = err
+		}
+		for key, ch := range c.pending {
+			close(ch)
+			delete(c.pending, key)
+		}
+		c.pendingMu.Unlock()
+
+		if closer, ok := c.writer.(io.Closer); ok {
+			_ = closer.Close()
+		}
+	}
+
+	if c.onConnectionLost != nil {
+		c.onConnectionLost(err)
+	}
+}
@@ -0,0 +1,35 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+)
+
+// LogHandler handles the log messages sent by the server
+type LogHandler func(ctx context.Context, msg types.LoggingMessageNotification)
+
+// SetLogLevel asks the server to send the log messages of the given level
+// and above. Servers send none before it is called.
+func (c *Client) SetLogLevel(ctx context.Context, level types.LoggingLevel) error {
+	if err := c.call(ctx, types.MethodLoggingSetLevel, types.SetLevelRequest{Level: level}, nil); err != nil {
+		return errors.Wrap(err, "failed to set logging level")
+	}
+	return nil
+}
+
+// OnLogMessage registers the handler of the log messages sent by the server,
+// replacing any previous one. It is called like the handlers registered
+// with OnNotification.
+func (c *Client) OnLogMessage(handler LogHandler) {
+	c.OnNotification(types.NotificationMessage, func(ctx context.Context, params json.RawMessage) {
+		var msg types.LoggingMessageNotification
+		if err := json.Unmarshal(params, &msg); err != nil {
+			return
+		}
+		handler(ctx, msg)
+	})
+}
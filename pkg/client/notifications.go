@@ -0,0 +1,144 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+)
+
+// NotificationHandler handles a notification sent by the server
+type NotificationHandler func(ctx context.Context, params json.RawMessage)
+
+// OnNotification registers the handler of the notifications with the given
+// method, replacing any previous one. Over stdio notifications are read
+// along with the responses; over HTTP they arrive on the event stream opened
+// by Listen. Handlers are called one at a time, in the order the
+// notifications arrive, on the goroutine reading them, so a handler that
+// calls the server must do so from another goroutine.
+func (c *Client) OnNotification(method string, handler NotificationHandler) {
+	c.handlersMu.Lock()
+	defer c.handlersMu.Unlock()
+	if c.notificationHandlers == nil {
+		c.notificationHandlers = make(map[string]NotificationHandler)
+	}
+	c.notificationHandlers[method] = handler
+}
+
+// handleNotification passes a notification to its handler, if any.
+// Progress and resource updates are passed to the handler of their request
+// or subscription first, and cancellations cancel the server request they
+// name.
+func (c *Client) handleNotification(ctx context.Context, msg *jsonrpc.Message) {
+	switch msg.Method {
+	case types.NotificationCancelled:
+		c.cancelIncoming(msg.Params)
+	case types.NotificationProgress:
+		c.handleProgress(msg.Params)
+	case types.NotificationResourceUpdated:
+		c.handleResourceUpdated(msg.Params)
+	}
+
+	c.handlersMu.RLock()
+	handler := c.notificationHandlers[msg.Method]
+	c.handlersMu.RUnlock()
+
+	if handler != nil {
+		handler(ctx, msg.Params)
+	}
+}
+
+// Listen opens the event stream of the session on an HTTP server and
+// handles the notifications and requests sent on it until ctx is done or
+// the server closes the stream. It must be called after Initialize. Over
+// stdio it returns immediately since notifications arrive with the responses.
+func (c *Client) Listen(ctx context.Context) error {
+	if c.isStdio() {
+		return nil
+	}
+
+	c.sessionMu.RLock()
+	sessionID := c.sessionID
+	c.sessionMu.RUnlock()
+	if sessionID == "" {
+		return errors.New("failed to listen: no session, call Initialize first")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url(), nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set(headerSessionID, sessionID)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to open event stream")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to open event stream: unexpected status code: %d", resp.StatusCode)
+	}
+
+	err = readEvents(resp.Body, func(msg *jsonrpc.Message) bool {
+		switch {
+		case msg.IsNotification():
+			c.handleNotification(ctx, msg)
+		case msg.IsRequest():
+			c.serveRequest(ctx, msg)
+		}
+		return true
+	})
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// readEvents reads server-sent events carrying JSON-RPC messages and passes
+// the messages to handle until it returns false or the stream ends. Events
+// that are not messages are skipped.
+func readEvents(r io.Reader, handle func(*jsonrpc.Message) bool) error {
+	reader := bufio.NewReader(r)
+	var data []string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return errors.Wrap(err, "failed to read event stream")
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+		if line != "" {
+			if value, ok := strings.CutPrefix(line, "data:"); ok {
+				data = append(data, strings.TrimPrefix(value, " "))
+			}
+			continue
+		}
+
+		// A blank line ends the event
+		if len(data) == 0 {
+			continue
+		}
+		var msg jsonrpc.Message
+		err = json.Unmarshal([]byte(strings.Join(data, "\n")), &msg)
+		data = data[:0]
+		if err != nil {
+			continue
+		}
+		if !handle(&msg) {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package client
+
+import (
+	"context"
+	"iter"
+
+	"github.com/pkg/errors"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+)
+
+// pageFunc fetches the page of a list starting at cursor
+type pageFunc[T any] func(ctx context.Context, cursor string) ([]T, string, error)
+
+// ListToolsPage lists a page of tools. The cursor is empty for the first page
+// and the returned cursor is empty on the last page.
+func (c *Client) ListToolsPage(ctx context.Context, cursor string) ([]types.Tool, string, error) {
+	var result types.ListToolsResult
+	if err := c.call(ctx, types.MethodToolsList, pageParams(cursor), &result); err != nil {
+		return nil, "", errors.Wrap(err, "failed to list tools")
+	}
+	c.outputSchemas.update(result.Tools)
+	return result.Tools, result.NextCursor, nil
+}
+
+// ListPromptsPage lists a page of prompts, see ListToolsPage
+func (c *Client) ListPromptsPage(ctx context.Context, cursor string) ([]types.Prompt, string, error) {
+	var result types.ListPromptsResult
+	if err := c.call(ctx, types.MethodPromptsList, pageParams(cursor), &result); err != nil {
+		return nil, "", errors.Wrap(err, "failed to list prompts")
+	}
+	return result.Prompts, result.NextCursor, nil
+}
+
+// ListResourcesPage lists a page of resources, see ListToolsPage
+func (c *Client) ListResourcesPage(ctx context.Context, cursor string) ([]types.Resource, string, error) {
+	var result types.ListResourcesResult
+	if err := c.call(ctx, types.MethodResourcesList, pageParams(cursor), &result); err != nil {
+		return nil, "", errors.Wrap(err, "failed to list resources")
+	}
+	return result.Resources, result.NextCursor, nil
+}
+
+// ListResourceTemplatesPage lists a page of resource templates, see
+// ListToolsPage
+func (c *Client) ListResourceTemplatesPage(ctx context.Context, cursor string) ([]types.ResourceTemplate, string, error) {
+	var result types.ListResourceTemplatesResult
+	if err := c.call(ctx, types.MethodResourcesTemplatesList, pageParams(cursor), &result); err != nil {
+		return nil, "", errors.Wrap(err, "failed to list resource templates")
+	}
+	return result.ResourceTemplates, result.NextCursor, nil
+}
+
+// ToolsIter iterates over the tools of every page, fetching pages as the
+// iteration goes. The iteration stops after yielding an error.
+//
+//	for tool, err := range c.ToolsIter(ctx) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func (c *Client) ToolsIter(ctx context.Context) iter.Seq2[types.Tool, error] {
+	return pages(ctx, c.ListToolsPage)
+}
+
+// PromptsIter iterates over the prompts of every page, see ToolsIter
+func (c *Client) PromptsIter(ctx context.Context) iter.Seq2[types.Prompt, error] {
+	return pages(ctx, c.ListPromptsPage)
+}
+
+// ResourcesIter iterates over the resources of every page, see ToolsIter
+func (c *Client) ResourcesIter(ctx context.Context) iter.Seq2[types.Resource, error] {
+	return pages(ctx, c.ListResourcesPage)
+}
+
+// ResourceTemplatesIter iterates over the resource templates of every page,
+// see ToolsIter
+func (c *Client) ResourceTemplatesIter(ctx context.Context) iter.Seq2[types.ResourceTemplate, error] {
+	return pages(ctx, c.ListResourceTemplatesPage)
+}
+
+// pageParams returns the params of a list request, omitted for the first
+// page so servers without pagination support see the same request as before
+func pageParams(cursor string) interface{} {
+	if cursor == "" {
+		return nil
+	}
+	return types.PaginatedRequest{Cursor: cursor}
+}
+
+// pages iterates over the items of every page of a list. A server returning
+// a cursor it already returned would loop forever, so that is an error.
+func pages[T any](ctx context.Context, fetch pageFunc[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		seen := make(map[string]bool)
+		cursor := ""
+		for {
+			items, next, err := fetch(ctx, cursor)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if next == "" {
+				return
+			}
+			if seen[next] {
+				yield(zero, errors.Errorf("server returned cursor %q twice", next))
+				return
+			}
+			seen[next] = true
+			cursor = next
+		}
+	}
+}
+
+// collect gathers the items of an iterator, stopping at the first error
+func collect[T any](seq iter.Seq2[T, error]) ([]T, error) {
+	var items []T
+	for item, err := range seq {
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
@@ -0,0 +1,82 @@
+package client
+
+import (
+	"encoding/json"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+)
+
+// ProgressHandler receives the progress of a request
+type ProgressHandler func(progress types.ProgressNotification)
+
+// CallOption configures a single call to the server
+type CallOption func(*callOptions)
+
+// callOptions holds the options of a call
+type callOptions struct {
+	onProgress ProgressHandler
+}
+
+// OnProgress asks the server to report the progress of the call, which is
+// passed to handler as it arrives. Over HTTP progress is streamed with the
+// response; over stdio it is read along with the responses.
+func OnProgress(handler ProgressHandler) CallOption {
+	return func(o *callOptions) {
+		o.onProgress = handler
+	}
+}
+
+// applyCallOptions collects the options of a call
+func applyCallOptions(opts []CallOption) callOptions {
+	var o callOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// trackProgress registers a progress handler under a new token and returns
+// the request metadata carrying the token, along with the function that
+// forgets the handler once the call has completed. Without a handler it
+// returns nil metadata.
+func (c *Client) trackProgress(handler ProgressHandler) (*types.RequestMeta, func()) {
+	if handler == nil {
+		return nil, func() {}
+	}
+
+	token := jsonrpc.NewStringID("progress-" + strconv.FormatInt(atomic.AddInt64(&c.nextProgressToken, 1), 10))
+	key := token.String()
+
+	c.progressMu.Lock()
+	if c.progressHandlers == nil {
+		c.progressHandlers = make(map[string]ProgressHandler)
+	}
+	c.progressHandlers[key] = handler
+	c.progressMu.Unlock()
+
+	return &types.RequestMeta{ProgressToken: &token}, func() {
+		c.progressMu.Lock()
+		delete(c.progressHandlers, key)
+		c.progressMu.Unlock()
+	}
+}
+
+// handleProgress passes a progress notification to the handler of its
+// request. Progress of requests that have completed is dropped.
+func (c *Client) handleProgress(params json.RawMessage) {
+	var progress types.ProgressNotification
+	if err := json.Unmarshal(params, &progress); err != nil {
+		return
+	}
+
+	c.progressMu.Lock()
+	handler := c.progressHandlers[progress.ProgressToken.String()]
+	c.progressMu.Unlock()
+
+	if handler != nil {
+		handler(progress)
+	}
+}
@@ -0,0 +1,134 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+)
+
+// SamplingHandler answers the sampling requests of the server by sampling a
+// language model
+type SamplingHandler func(ctx context.Context, req *types.CreateMessageRequest) (*types.CreateMessageResponse, error)
+
+// ElicitationHandler asks the user for the structured input a server
+// requests. It returns the action of the user, and the submitted data when
+// they accept.
+type ElicitationHandler func(ctx context.Context, req *types.ElicitRequest) (*types.ElicitResult, error)
+
+// clientCapabilities returns the capabilities the client declares, derived
+// from the handlers it was given
+func (c *Client) clientCapabilities() types.ClientCapabilities {
+	var caps types.ClientCapabilities
+	if c.samplingHandler != nil {
+		caps.Sampling = &types.SamplingCapability{}
+	}
+	if c.elicitationHandler != nil {
+		caps.Elicitation = &types.ElicitationCapability{}
+	}
+	if c.rootsProvider() != nil {
+		caps.Roots = &types.RootsCapability{ListChanged: true}
+	}
+	return caps
+}
+
+// serveRequest answers a request sent by the server in its own goroutine,
+// so the messages that follow are still read. The server can cancel it with
+// notifications/cancelled.
+func (c *Client) serveRequest(ctx context.Context, msg *jsonrpc.Message) {
+	key := msg.RequestID().String()
+	reqCtx, cancel := context.WithCancel(ctx)
+
+	c.incomingMu.Lock()
+	if c.incoming == nil {
+		c.incoming = make(map[string]context.CancelFunc)
+	}
+	c.incoming[key] = cancel
+	c.incomingMu.Unlock()
+
+	go func() {
+		defer func() {
+			c.incomingMu.Lock()
+			delete(c.incoming, key)
+			c.incomingMu.Unlock()
+			cancel()
+		}()
+
+		resp := c.handleRequest(reqCtx, msg)
+		if reqCtx.Err() != nil {
+			// The server no longer waits for the response
+			return
+		}
+		// Failures mean the connection is gone, which the server notices
+		_ = c.reply(context.WithoutCancel(ctx), resp)
+	}()
+}
+
+// handleRequest answers a request sent by the server
+func (c *Client) handleRequest(ctx context.Context, msg *jsonrpc.Message) *jsonrpc.Response {
+	result, err := c.answer(ctx, msg)
+	if err != nil {
+		return jsonrpc.NewErrorResponse(msg.RequestID(), jsonrpc.ErrorFrom(err))
+	}
+	resp, err := jsonrpc.NewResponse(msg.RequestID(), result)
+	if err != nil {
+		return jsonrpc.NewErrorResponse(msg.RequestID(), jsonrpc.ErrorFrom(errors.Wrap(err, "failed to encode result")))
+	}
+	return resp
+}
+
+// answer returns the result of a request sent by the server
+func (c *Client) answer(ctx context.Context, msg *jsonrpc.Message) (interface{}, error) {
+	switch {
+	case msg.Method == types.MethodPing:
+		return struct{}{}, nil
+	case msg.Method == types.MethodSamplingCreateMessage && c.samplingHandler != nil:
+		var req types.CreateMessageRequest
+		if err := msg.UnmarshalParams(&req); err != nil {
+			return nil, err
+		}
+		return c.samplingHandler(ctx, &req)
+	case msg.Method == types.MethodElicitationCreate && c.elicitationHandler != nil:
+		var req types.ElicitRequest
+		if err := msg.UnmarshalParams(&req); err != nil {
+			return nil, err
+		}
+		return c.elicitationHandler(ctx, &req)
+	case msg.Method == types.MethodRootsList:
+		if provider := c.rootsProvider(); provider != nil {
+			return c.listRoots(ctx, provider)
+		}
+	}
+	return nil, jsonrpc.NewError(jsonrpc.CodeMethodNotFound, "method not found: "+msg.Method)
+}
+
+// cancelIncoming cancels a request of the server the client is answering
+func (c *Client) cancelIncoming(params json.RawMessage) {
+	var cancelled types.CancelledNotification
+	if err := json.Unmarshal(params, &cancelled); err != nil {
+		return
+	}
+
+	c.incomingMu.Lock()
+	cancel, ok := c.incoming[cancelled.RequestID.String()]
+	c.incomingMu.Unlock()
+	if ok {
+		cancel()
+	}
+}
+
+// reply sends the response to a request of the server
+func (c *Client) reply(ctx context.Context, resp *jsonrpc.Response) error {
+	if c.isStdio() {
+		return c.send(resp)
+	}
+
+	httpResp, err := c.post(ctx, resp)
+	if err != nil {
+		return err
+	}
+	return httpResp.Body.Close()
+}
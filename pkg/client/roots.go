@@ -0,0 +1,63 @@
+package client
+
+import (
+	"context"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+)
+
+// RootsProvider returns the roots the client grants the server access to
+type RootsProvider func(ctx context.Context) ([]types.Root, error)
+
+// StaticRoots returns a provider of a fixed list of roots
+func StaticRoots(roots ...types.Root) RootsProvider {
+	return func(ctx context.Context) ([]types.Root, error) {
+		return roots, nil
+	}
+}
+
+// SetRoots replaces the roots of the client with a fixed list and tells the
+// server they changed. Called before Initialize, it makes the client declare
+// the roots capability; a client initialized without it keeps the roots to
+// itself until it initializes again.
+func (c *Client) SetRoots(ctx context.Context, roots ...types.Root) error {
+	c.rootsMu.Lock()
+	c.roots = StaticRoots(roots...)
+	c.rootsMu.Unlock()
+
+	if c.capabilities == nil {
+		// Not initialized yet, the server lists them when it needs them
+		return nil
+	}
+	return c.RootsChanged(ctx)
+}
+
+// RootsChanged tells the server the roots of the client changed, so it lists
+// them again. Clients whose RootsProvider returns a different list call it.
+// It does nothing unless the client declared roots list changes during
+// initialization.
+func (c *Client) RootsChanged(ctx context.Context) error {
+	if c.declared.Roots == nil || !c.declared.Roots.ListChanged {
+		return nil
+	}
+	return c.notify(ctx, types.NotificationRootsListChanged, nil)
+}
+
+// rootsProvider returns the provider of the roots, nil if the client has none
+func (c *Client) rootsProvider() RootsProvider {
+	c.rootsMu.Lock()
+	defer c.rootsMu.Unlock()
+	return c.roots
+}
+
+// listRoots answers roots/list requests
+func (c *Client) listRoots(ctx context.Context, provider RootsProvider) (*types.ListRootsResult, error) {
+	roots, err := provider(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if roots == nil {
+		roots = []types.Root{}
+	}
+	return &types.ListRootsResult{Roots: roots}, nil
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+)
+
+// ResourceUpdateHandler is called when a subscribed resource changes
+type ResourceUpdateHandler func(uri string)
+
+// Subscribe subscribes to updates of a resource. handler is called with the
+// URI of the resource whenever the server reports that it changed. Over HTTP
+// updates arrive on the event stream opened by Listen.
+func (c *Client) Subscribe(ctx context.Context, uri string, handler ResourceUpdateHandler) error {
+	// The handler is in place before the server can send the first update
+	c.subscriptionsMu.Lock()
+	if c.subscriptions == nil {
+		c.subscriptions = make(map[string]ResourceUpdateHandler)
+	}
+	previous, subscribed := c.subscriptions[uri]
+	c.subscriptions[uri] = handler
+	c.subscriptionsMu.Unlock()
+
+	req := types.SubscribeRequest{URI: uri}
+	if err := c.call(ctx, types.MethodResourcesSubscribe, req, nil); err != nil {
+		c.subscriptionsMu.Lock()
+		if subscribed {
+			c.subscriptions[uri] = previous
+		} else {
+			delete(c.subscriptions, uri)
+		}
+		c.subscriptionsMu.Unlock()
+		return errors.Wrap(err, "failed to subscribe to resource")
+	}
+	return nil
+}
+
+// Unsubscribe cancels the subscription to a resource
+func (c *Client) Unsubscribe(ctx context.Context, uri string) error {
+	req := types.UnsubscribeRequest{URI: uri}
+	if err := c.call(ctx, types.MethodResourcesUnsubscribe, req, nil); err != nil {
+		return errors.Wrap(err, "failed to unsubscribe from resource")
+	}
+
+	c.subscriptionsMu.Lock()
+	delete(c.subscriptions, uri)
+	c.subscriptionsMu.Unlock()
+	return nil
+}
+
+// handleResourceUpdated passes a resource update to the handler of its
+// subscription
+func (c *Client) handleResourceUpdated(params json.RawMessage) {
+	var update types.ResourceUpdatedNotification
+	if err := json.Unmarshal(params, &update); err != nil {
+		return
+	}
+
+	c.subscriptionsMu.Lock()
+	handler := c.subscriptions[update.URI]
+	c.subscriptionsMu.Unlock()
+
+	if handler != nil {
+		handler(update.URI)
+	}
+}
@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonschema"
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+)
+
+// outputSchemas holds the compiled output schemas of the tools seen by
+// ListTools, so results can be validated on receipt
+type outputSchemas struct {
+	mu      sync.RWMutex
+	schemas map[string]*jsonschema.Validator
+}
+
+// update records the output schemas of listed tools. Tools whose schema
+// does not compile are not validated.
+func (o *outputSchemas) update(tools []types.Tool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.schemas == nil {
+		o.schemas = make(map[string]*jsonschema.Validator)
+	}
+	for _, tool := range tools {
+		delete(o.schemas, tool.Name)
+		if len(tool.OutputSchema) == 0 {
+			continue
+		}
+		if v, err := jsonschema.Compile(tool.OutputSchema); err == nil {
+			o.schemas[tool.Name] = v
+		}
+	}
+}
+
+// check validates the structured content of a successful result against
+// the output schema of the tool, if known
+func (o *outputSchemas) check(name string, result *types.CallToolResult) error {
+	o.mu.RLock()
+	v := o.schemas[name]
+	o.mu.RUnlock()
+
+	if v == nil || result.IsError {
+		return nil
+	}
+	if result.StructuredContent == nil {
+		return errors.New("tool " + name + " has an output schema but returned no structured content")
+	}
+
+	res, err := v.Validate(result.StructuredContent)
+	if err != nil {
+		return err
+	}
+	if !res.Valid {
+		return errors.New("invalid structured content from tool " + name + ": " + jsonschema.FormatErrors(res))
+	}
+	return nil
+}
+
+// CallToolAs calls a tool and decodes its structured content into Out.
+// Results flagged as errors by the tool are returned as errors holding their
+// text.
+func CallToolAs[Out any](ctx context.Context, c *Client, name string, args map[string]interface{}, opts ...CallOption) (Out, error) {
+	var out Out
+
+	result, err := c.CallTool(ctx, name, args, opts...)
+	if err != nil {
+		return out, err
+	}
+	if result.IsError {
+		return out, errors.New("tool " + name + " failed: " + result.Text())
+	}
+	if err := result.DecodeStructuredContent(&out); err != nil {
+		return out, errors.Wrap(err, "failed to decode result of tool "+name)
+	}
+	return out, nil
+}
@@ -0,0 +1,290 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Version is the JSON-RPC protocol version carried by every message
+const Version = "2.0"
+
+// Standard JSON-RPC 2.0 error codes
+const (
+	// CodeParseError indicates the message was not valid JSON
+	CodeParseError = -32700
+	// CodeInvalidRequest indicates the message is not a valid request object
+	CodeInvalidRequest = -32600
+	// CodeMethodNotFound indicates the method does not exist or is not available
+	CodeMethodNotFound = -32601
+	// CodeInvalidParams indicates invalid method parameters
+	CodeInvalidParams = -32602
+	// CodeInternalError indicates an internal JSON-RPC error
+	CodeInternalError = -32603
+)
+
+// ID identifies a request. It holds either a string or a number and keeps its
+// original JSON encoding so it can be echoed back verbatim.
+type ID struct {
+	raw json.RawMessage
+}
+
+// NewIntID creates a numeric request ID
+func NewIntID(n int64) ID {
+	return ID{raw: json.RawMessage(strconv.FormatInt(n, 10))}
+}
+
+// NewStringID creates a string request ID
+func NewStringID(s string) ID {
+	raw, _ := json.Marshal(s)
+	return ID{raw: raw}
+}
+
+// IsZero reports whether the ID is unset
+func (id ID) IsZero() bool {
+	return len(id.raw) == 0
+}
+
+// String returns the JSON encoding of the ID, suitable for use as a map key
+func (id ID) String() string {
+	if id.IsZero() {
+		return "null"
+	}
+	return string(id.raw)
+}
+
+// MarshalJSON implements json.Marshaler
+func (id ID) MarshalJSON() ([]byte, error) {
+	if id.IsZero() {
+		return []byte("null"), nil
+	}
+	return id.raw, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (id *ID) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		id.raw = nil
+		return nil
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch v.(type) {
+	case string, float64:
+		id.raw = append(json.RawMessage(nil), data...)
+		return nil
+	default:
+		return fmt.Errorf("jsonrpc: id must be a string or a number, got %s", data)
+	}
+}
+
+// ErrorObject represents a JSON-RPC error
+type ErrorObject struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// NewError creates a new ErrorObject
+func NewError(code int, message string) *ErrorObject {
+	return &ErrorObject{
+		Code:    code,
+		Message: message,
+	}
+}
+
+// Error implements the error interface
+func (e *ErrorObject) Error() string {
+	return fmt.Sprintf("jsonrpc error %d: %s", e.Code, e.Message)
+}
+
+// Coder is implemented by errors that know their JSON-RPC representation
+type Coder interface {
+	JSONRPCError() *ErrorObject
+}
+
+// ErrorFrom converts err into an ErrorObject. *ErrorObject values are returned
+// as is, errors implementing Coder provide their own representation, and any
+// other error is reported as an internal error.
+func ErrorFrom(err error) *ErrorObject {
+	if err == nil {
+		return nil
+	}
+
+	var obj *ErrorObject
+	if errors.As(err, &obj) {
+		return obj
+	}
+	var coder Coder
+	if errors.As(err, &coder) {
+		return coder.JSONRPCError()
+	}
+	return NewError(CodeInternalError, err.Error())
+}
+
+// Request represents a JSON-RPC request that expects a response
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      ID              `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// NewRequest creates a new request with params encoded as JSON
+func NewRequest(id ID, method string, params interface{}) (*Request, error) {
+	raw, err := marshalParams(params)
+	if err != nil {
+		return nil, err
+	}
+	return &Request{
+		JSONRPC: Version,
+		ID:      id,
+		Method:  method,
+		Params:  raw,
+	}, nil
+}
+
+// Notification represents a JSON-RPC notification, which is never answered
+type Notification struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// NewNotification creates a new notification with params encoded as JSON
+func NewNotification(method string, params interface{}) (*Notification, error) {
+	raw, err := marshalParams(params)
+	if err != nil {
+		return nil, err
+	}
+	return &Notification{
+		JSONRPC: Version,
+		Method:  method,
+		Params:  raw,
+	}, nil
+}
+
+// Response represents a JSON-RPC response carrying either a result or an error
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      ID              `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *ErrorObject    `json:"error,omitempty"`
+}
+
+// NewResponse creates a successful response with result encoded as JSON
+func NewResponse(id ID, result interface{}) (*Response, error) {
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: failed to marshal result: %w", err)
+	}
+	return &Response{
+		JSONRPC: Version,
+		ID:      id,
+		Result:  raw,
+	}, nil
+}
+
+// NewErrorResponse creates an error response
+func NewErrorResponse(id ID, err *ErrorObject) *Response {
+	return &Response{
+		JSONRPC: Version,
+		ID:      id,
+		Error:   err,
+	}
+}
+
+// Message is the union of every JSON-RPC message shape. It is used to decode
+// incoming data before it is known whether it is a request, a notification or
+// a response.
+type Message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *ID             `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *ErrorObject    `json:"error,omitempty"`
+}
+
+// Decode parses a single JSON-RPC message. The returned ErrorObject is ready
+// to be sent back to the peer when the data is not a valid message: a parse
+// error when it is not JSON, an invalid request when it is JSON of the wrong
+// shape.
+func Decode(data []byte) (*Message, *ErrorObject) {
+	var msg Message
+	if err := json.Unmarshal(data, &msg); err != nil {
+		if !json.Valid(data) {
+			return nil, NewError(CodeParseError, "parse error: "+err.Error())
+		}
+		return nil, NewError(CodeInvalidRequest, "invalid request: "+err.Error())
+	}
+	if msg.JSONRPC != Version {
+		return &msg, NewError(CodeInvalidRequest, "invalid request: jsonrpc must be \"2.0\"")
+	}
+	if msg.Method == "" && !msg.IsResponse() {
+		return &msg, NewError(CodeInvalidRequest, "invalid request: missing method")
+	}
+	return &msg, nil
+}
+
+// IsRequest reports whether the message is a request expecting a response
+func (m *Message) IsRequest() bool {
+	return m.Method != "" && m.ID != nil && !m.ID.IsZero()
+}
+
+// IsNotification reports whether the message is a notification
+func (m *Message) IsNotification() bool {
+	return m.Method != "" && (m.ID == nil || m.ID.IsZero())
+}
+
+// IsResponse reports whether the message is a response to an earlier request
+func (m *Message) IsResponse() bool {
+	return m.Method == "" && (m.Result != nil || m.Error != nil)
+}
+
+// RequestID returns the message ID, or the zero ID when it has none
+func (m *Message) RequestID() ID {
+	if m.ID == nil {
+		return ID{}
+	}
+	return *m.ID
+}
+
+// Response converts a response message into a Response
+func (m *Message) Response() *Response {
+	return &Response{
+		JSONRPC: m.JSONRPC,
+		ID:      m.RequestID(),
+		Result:  m.Result,
+		Error:   m.Error,
+	}
+}
+
+// UnmarshalParams decodes the message params into v. Missing params leave v
+// untouched.
+func (m *Message) UnmarshalParams(v interface{}) *ErrorObject {
+	if len(m.Params) == 0 || bytes.Equal(bytes.TrimSpace(m.Params), []byte("null")) {
+		return nil
+	}
+	if err := json.Unmarshal(m.Params, v); err != nil {
+		return NewError(CodeInvalidParams, "invalid params: "+err.Error())
+	}
+	return nil
+}
+
+func marshalParams(params interface{}) (json.RawMessage, error) {
+	if params == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: failed to marshal params: %w", err)
+	}
+	return raw, nil
+}
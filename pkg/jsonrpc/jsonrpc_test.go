@@ -0,0 +1,151 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestID_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{name: "number", data: `42`, want: `42`},
+		{name: "string", data: `"abc"`, want: `"abc"`},
+		{name: "null", data: `null`, want: `null`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var id ID
+			require.NoError(t, json.Unmarshal([]byte(tt.data), &id))
+			assert.Equal(t, tt.want, id.String())
+
+			data, err := json.Marshal(id)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.data, string(data))
+		})
+	}
+
+	var id ID
+	assert.Error(t, json.Unmarshal([]byte(`{"a":1}`), &id))
+	assert.Equal(t, NewIntID(7).String(), "7")
+	assert.Equal(t, NewStringID("7").String(), `"7"`)
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("request", func(t *testing.T) {
+		msg, rpcErr := Decode([]byte(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+		require.Nil(t, rpcErr)
+		assert.True(t, msg.IsRequest())
+		assert.False(t, msg.IsNotification())
+		assert.Equal(t, "1", msg.RequestID().String())
+	})
+
+	t.Run("notification", func(t *testing.T) {
+		msg, rpcErr := Decode([]byte(`{"jsonrpc":"2.0","method":"initialized"}`))
+		require.Nil(t, rpcErr)
+		assert.True(t, msg.IsNotification())
+		assert.False(t, msg.IsRequest())
+	})
+
+	t.Run("response", func(t *testing.T) {
+		msg, rpcErr := Decode([]byte(`{"jsonrpc":"2.0","id":"a","result":{}}`))
+		require.Nil(t, rpcErr)
+		assert.True(t, msg.IsResponse())
+		assert.Equal(t, `"a"`, msg.Response().ID.String())
+	})
+
+	t.Run("parse error", func(t *testing.T) {
+		msg, rpcErr := Decode([]byte(`not json`))
+		assert.Nil(t, msg)
+		require.NotNil(t, rpcErr)
+		assert.Equal(t, CodeParseError, rpcErr.Code)
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		for _, data := range []string{
+			`{"jsonrpc":"2.0","id":{"a":1},"method":"ping"}`,
+			`{"jsonrpc":"2.0","id":[1],"method":"ping"}`,
+			`{"jsonrpc":"2.0","id":true,"method":"ping"}`,
+			`{"jsonrpc":"2.0","id":1,"method":2}`,
+			`[1,2]`,
+			`"ping"`,
+		} {
+			msg, rpcErr := Decode([]byte(data))
+			assert.Nil(t, msg, data)
+			require.NotNil(t, rpcErr, data)
+			assert.Equal(t, CodeInvalidRequest, rpcErr.Code, data)
+		}
+	})
+
+	t.Run("wrong version", func(t *testing.T) {
+		msg, rpcErr := Decode([]byte(`{"jsonrpc":"1.0","id":3,"method":"ping"}`))
+		require.NotNil(t, rpcErr)
+		assert.Equal(t, CodeInvalidRequest, rpcErr.Code)
+		assert.Equal(t, "3", msg.RequestID().String())
+	})
+
+	t.Run("missing method", func(t *testing.T) {
+		_, rpcErr := Decode([]byte(`{"jsonrpc":"2.0","id":3}`))
+		require.NotNil(t, rpcErr)
+		assert.Equal(t, CodeInvalidRequest, rpcErr.Code)
+	})
+}
+
+func TestMessage_UnmarshalParams(t *testing.T) {
+	msg, rpcErr := Decode([]byte(`{"jsonrpc":"2.0","id":1,"method":"m","params":{"name":"x"}}`))
+	require.Nil(t, rpcErr)
+
+	var params struct {
+		Name string `json:"name"`
+	}
+	require.Nil(t, msg.UnmarshalParams(&params))
+	assert.Equal(t, "x", params.Name)
+
+	var wrong []string
+	rpcErr = msg.UnmarshalParams(&wrong)
+	require.NotNil(t, rpcErr)
+	assert.Equal(t, CodeInvalidParams, rpcErr.Code)
+}
+
+type codedError struct{}
+
+func (codedError) Error() string { return "coded" }
+
+func (codedError) JSONRPCError() *ErrorObject { return NewError(404, "Tool not found") }
+
+func TestErrorFrom(t *testing.T) {
+	assert.Nil(t, ErrorFrom(nil))
+
+	obj := NewError(CodeInvalidParams, "bad")
+	assert.Same(t, obj, ErrorFrom(obj))
+	assert.Same(t, obj, ErrorFrom(fmt.Errorf("wrapped: %w", obj)))
+
+	converted := ErrorFrom(codedError{})
+	assert.Equal(t, 404, converted.Code)
+	assert.Equal(t, "Tool not found", converted.Message)
+
+	internal := ErrorFrom(errors.New("boom"))
+	assert.Equal(t, CodeInternalError, internal.Code)
+	assert.Equal(t, "boom", internal.Message)
+}
+
+func TestNewResponse(t *testing.T) {
+	resp, err := NewResponse(NewIntID(1), map[string]string{"ok": "yes"})
+	require.NoError(t, err)
+
+	data, err := json.Marshal(resp)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":1,"result":{"ok":"yes"}}`, string(data))
+
+	data, err = json.Marshal(NewErrorResponse(ID{}, NewError(CodeParseError, "parse error")))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"parse error"}}`, string(data))
+}
@@ -0,0 +1,406 @@
+// Package jsonschema generates JSON schemas from Go types, so tool inputs and
+// outputs can be described by the structs that hold them.
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Schema is a JSON schema. Only the keywords the generator emits are
+// supported.
+type Schema struct {
+	Type                 string             `json:"type,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	ContentEncoding      string             `json:"contentEncoding,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Default              interface{}        `json:"default,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum     *float64           `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     *float64           `json:"exclusiveMaximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+
+	// Nullable also accepts null, which encoding/json produces for nil
+	// pointers, slices and maps. The type is then encoded as [type, "null"].
+	Nullable bool `json:"-"`
+}
+
+// MarshalJSON encodes the type of nullable schemas as a list
+func (s Schema) MarshalJSON() ([]byte, error) {
+	type alias Schema
+	if !s.Nullable || s.Type == "" {
+		return json.Marshal(alias(s))
+	}
+
+	aux := struct {
+		alias
+		Type []string      `json:"type"`
+		Enum []interface{} `json:"enum,omitempty"`
+	}{alias: alias(s), Type: []string{s.Type, "null"}}
+	if len(s.Enum) > 0 {
+		aux.Enum = append(append([]interface{}(nil), s.Enum...), nil)
+	}
+	return json.Marshal(aux)
+}
+
+// UnmarshalJSON decodes a type given as a list of a type and "null" into a
+// nullable schema
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	type alias Schema
+	aux := struct {
+		*alias
+		Type json.RawMessage `json:"type,omitempty"`
+	}{alias: (*alias)(s)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	s.Type, s.Nullable = "", false
+	if len(aux.Type) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(aux.Type, &s.Type); err == nil {
+		return nil
+	}
+	var types []string
+	if err := json.Unmarshal(aux.Type, &types); err != nil {
+		return errors.Wrap(err, "invalid schema type")
+	}
+	for _, t := range types {
+		switch {
+		case t == "null":
+			s.Nullable = true
+		case s.Type == "":
+			s.Type = t
+		default:
+			return errors.Errorf("unsupported schema types %v", types)
+		}
+	}
+	return nil
+}
+
+// Marshal encodes the schema
+func (s *Schema) Marshal() (json.RawMessage, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode schema")
+	}
+	return data, nil
+}
+
+// For returns the schema of T, see Reflect
+func For[T any]() (*Schema, error) {
+	return Reflect(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+// Reflect returns the schema of a Go type, following the encoding/json
+// rules for field names. Struct fields are required unless they are pointers
+// or tagged omitempty, and structs reject unknown properties. Nested
+// pointers, slices and maps are nullable, since encoding/json encodes them as
+// null when they are nil. Fields can be further described with a jsonschema
+// tag holding comma separated key=value pairs:
+//
+//	Unit string `json:"unit" jsonschema:"description=Temperature unit,enum=celsius|fahrenheit"`
+//
+// The supported keys are title, description, format, pattern, enum (values
+// separated by |), default, minimum, maximum, exclusiveMinimum,
+// exclusiveMaximum, minLength, maxLength, minItems, maxItems, and required
+// or optional, which take no value.
+func Reflect(t reflect.Type) (*Schema, error) {
+	r := &reflector{seen: make(map[reflect.Type]bool)}
+	return r.reflect(t)
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// reflector builds schemas and detects recursive types, which it cannot
+// describe without references
+type reflector struct {
+	seen map[reflect.Type]bool
+}
+
+func (r *reflector) reflect(t reflect.Type) (*Schema, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}, nil
+	case rawMessageType:
+		return &Schema{}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}, nil
+	case reflect.String:
+		return &Schema{Type: "string"}, nil
+	case reflect.Interface:
+		return &Schema{}, nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// encoding/json encodes byte slices as base64 strings
+			return &Schema{Type: "string", ContentEncoding: "base64"}, nil
+		}
+		items, err := r.reflectValue(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "array", Items: items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, errors.Errorf("unsupported map key type %s", t.Key())
+		}
+		values, err := r.reflectValue(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "object", AdditionalProperties: values}, nil
+	case reflect.Struct:
+		return r.reflectStruct(t)
+	default:
+		return nil, errors.Errorf("unsupported type %s", t)
+	}
+}
+
+// reflectValue returns the schema of a value nested in another one, which
+// is nullable for pointers, slices and maps
+func (r *reflector) reflectValue(t reflect.Type) (*Schema, error) {
+	schema, err := r.reflect(t)
+	if err != nil {
+		return nil, err
+	}
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map:
+		schema.Nullable = schema.Type != ""
+	}
+	return schema, nil
+}
+
+func (r *reflector) reflectStruct(t reflect.Type) (*Schema, error) {
+	if r.seen[t] {
+		return nil, errors.Errorf("recursive type %s is not supported", t)
+	}
+	r.seen[t] = true
+	defer delete(r.seen, t)
+
+	schema := &Schema{
+		Type:                 "object",
+		Properties:           make(map[string]*Schema),
+		AdditionalProperties: false,
+	}
+	if err := r.addFields(schema, t); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// addFields adds the fields of a struct to the schema, flattening embedded
+// structs like encoding/json does
+func (r *reflector) addFields(schema *Schema, t reflect.Type) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, omitEmpty, skip := jsonName(field)
+		if skip {
+			continue
+		}
+
+		fieldType := field.Type
+		if field.Anonymous && name == "" {
+			for fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct {
+				if err := r.addFields(schema, fieldType); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		prop, err := r.reflectValue(fieldType)
+		if err != nil {
+			return errors.Wrapf(err, "field %s", field.Name)
+		}
+		required := !omitEmpty && field.Type.Kind() != reflect.Pointer
+		if tag, ok := field.Tag.Lookup("jsonschema"); ok {
+			if required, err = applyTag(prop, fieldType, tag, required); err != nil {
+				return errors.Wrapf(err, "field %s", field.Name)
+			}
+		}
+
+		schema.Properties[name] = prop
+		if required {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	return nil
+}
+
+// jsonName returns the name of a field in JSON and whether it is omitted
+// when empty or skipped entirely
+func jsonName(field reflect.StructField) (name string, omitEmpty, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	parts := strings.Split(tag, ",")
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" || opt == "omitzero" {
+			omitEmpty = true
+		}
+	}
+	if !field.Anonymous && !field.IsExported() {
+		return "", false, true
+	}
+	return parts[0], omitEmpty, false
+}
+
+// tagKeys are the keys of the jsonschema tag
+var tagKeys = map[string]bool{
+	"title": true, "description": true, "format": true, "pattern": true,
+	"enum": true, "default": true, "minimum": true, "maximum": true,
+	"exclusiveMinimum": true, "exclusiveMaximum": true, "minLength": true,
+	"maxLength": true, "minItems": true, "maxItems": true,
+	"required": true, "optional": true,
+}
+
+// splitTag splits a jsonschema tag into its key=value pairs. Commas that are
+// not followed by a known key belong to the previous value, so descriptions
+// may contain commas.
+func splitTag(tag string) []string {
+	var pairs []string
+	for _, part := range strings.Split(tag, ",") {
+		key, _, _ := strings.Cut(part, "=")
+		if len(pairs) > 0 && !tagKeys[strings.TrimSpace(key)] {
+			pairs[len(pairs)-1] += "," + part
+			continue
+		}
+		pairs = append(pairs, part)
+	}
+	return pairs
+}
+
+// applyTag applies a jsonschema tag to the schema of a field and returns
+// whether the field is required
+func applyTag(schema *Schema, t reflect.Type, tag string, required bool) (bool, error) {
+	for _, pair := range splitTag(tag) {
+		key, value, _ := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+
+		var err error
+		switch key {
+		case "":
+		case "required":
+			required = true
+		case "optional":
+			required = false
+		case "title":
+			schema.Title = value
+		case "description":
+			schema.Description = value
+		case "format":
+			schema.Format = value
+		case "pattern":
+			schema.Pattern = value
+		case "enum":
+			for _, v := range strings.Split(value, "|") {
+				parsed, perr := parseValue(t, v)
+				if perr != nil {
+					return false, perr
+				}
+				schema.Enum = append(schema.Enum, parsed)
+			}
+		case "default":
+			schema.Default, err = parseValue(t, value)
+		case "minimum":
+			schema.Minimum, err = parseFloat(value)
+		case "maximum":
+			schema.Maximum, err = parseFloat(value)
+		case "exclusiveMinimum":
+			schema.ExclusiveMinimum, err = parseFloat(value)
+		case "exclusiveMaximum":
+			schema.ExclusiveMaximum, err = parseFloat(value)
+		case "minLength":
+			schema.MinLength, err = parseInt(value)
+		case "maxLength":
+			schema.MaxLength, err = parseInt(value)
+		case "minItems":
+			schema.MinItems, err = parseInt(value)
+		case "maxItems":
+			schema.MaxItems, err = parseInt(value)
+		default:
+			return false, errors.Errorf("unknown jsonschema tag key %q", key)
+		}
+		if err != nil {
+			return false, errors.Wrapf(err, "invalid %s", key)
+		}
+	}
+	return required, nil
+}
+
+// parseValue parses a tag value as a value of the field type
+func parseValue(t reflect.Type, value string) (interface{}, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return strconv.ParseBool(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseInt(value, 10, 64)
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(value, 64)
+	default:
+		return value, nil
+	}
+}
+
+func parseFloat(value string) (*float64, error) {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+func parseInt(value string) (*int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
@@ -0,0 +1,124 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type base struct {
+	ID string `json:"id" jsonschema:"description=Unique ID, assigned by the server"`
+}
+
+type weatherRequest struct {
+	base
+	City    string            `json:"city" jsonschema:"minLength=1"`
+	Unit    string            `json:"unit,omitempty" jsonschema:"enum=celsius|fahrenheit,default=celsius"`
+	Days    *int              `json:"days" jsonschema:"minimum=1,maximum=7"`
+	Tags    []string          `json:"tags,omitempty" jsonschema:"maxItems=3"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Since   time.Time         `json:"since" jsonschema:"optional"`
+	Raw     []byte            `json:"raw,omitempty"`
+	Ignored string            `json:"-"`
+	hidden  string
+}
+
+func TestReflect(t *testing.T) {
+	schema, err := For[weatherRequest]()
+	require.NoError(t, err)
+
+	data, err := schema.Marshal()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"id": {"type": "string", "description": "Unique ID, assigned by the server"},
+			"city": {"type": "string", "minLength": 1},
+			"unit": {"type": "string", "enum": ["celsius", "fahrenheit"], "default": "celsius"},
+			"days": {"type": ["integer", "null"], "minimum": 1, "maximum": 7},
+			"tags": {"type": ["array", "null"], "items": {"type": "string"}, "maxItems": 3},
+			"labels": {"type": ["object", "null"], "additionalProperties": {"type": "string"}},
+			"since": {"type": "string", "format": "date-time"},
+			"raw": {"type": ["string", "null"], "contentEncoding": "base64"}
+		},
+		"required": ["id", "city"],
+		"additionalProperties": false
+	}`, string(data))
+}
+
+func TestReflect_Nullable(t *testing.T) {
+	type nullable struct {
+		Level  *string          `json:"level" jsonschema:"enum=low|high"`
+		Nested []*base          `json:"nested"`
+		Values map[string][]int `json:"values"`
+	}
+
+	schema, err := For[*nullable]()
+	require.NoError(t, err)
+	data, err := schema.Marshal()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"level": {"type": ["string", "null"], "enum": ["low", "high", null]},
+			"nested": {"type": ["array", "null"], "items": {
+				"type": ["object", "null"],
+				"properties": {"id": {"type": "string", "description": "Unique ID, assigned by the server"}},
+				"required": ["id"],
+				"additionalProperties": false
+			}},
+			"values": {"type": ["object", "null"], "additionalProperties": {"type": ["array", "null"], "items": {"type": "integer"}}}
+		},
+		"required": ["nested", "values"],
+		"additionalProperties": false
+	}`, string(data))
+
+	// Nil values validate
+	validator, err := Compile(data)
+	require.NoError(t, err)
+	result, err := validator.Validate(nullable{})
+	require.NoError(t, err)
+	assert.True(t, result.Valid, FormatErrors(result))
+
+	var decoded Schema
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, "object", decoded.Type)
+	assert.True(t, decoded.Properties["level"].Nullable)
+	assert.Equal(t, "string", decoded.Properties["level"].Type)
+	assert.Error(t, json.Unmarshal([]byte(`{"type":["string","integer"]}`), &decoded))
+}
+
+func TestReflect_Errors(t *testing.T) {
+	type recursive struct {
+		Children []recursive `json:"children"`
+	}
+	type badTag struct {
+		N int `json:"n" jsonschema:"minimum=low"`
+	}
+	type unknownKey struct {
+		N int `json:"n" jsonschema:"color=red"`
+	}
+
+	_, err := For[recursive]()
+	assert.Error(t, err)
+	_, err = For[badTag]()
+	assert.Error(t, err)
+	_, err = For[unknownKey]()
+	assert.Error(t, err)
+	_, err = For[chan int]()
+	assert.Error(t, err)
+
+	// Non-recursive reuse of a type is fine
+	type pair struct {
+		A base `json:"a"`
+		B base `json:"b"`
+	}
+	schema, err := For[pair]()
+	require.NoError(t, err)
+	data, err := json.Marshal(schema.Properties["b"])
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"id"`)
+}
@@ -0,0 +1,98 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/xeipuuv/gojsonschema"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/validation/core"
+)
+
+// Validator validates values against a compiled schema. It is safe for
+// concurrent use.
+type Validator struct {
+	schema *gojsonschema.Schema
+}
+
+// Compile compiles a schema so values can be validated against it
+// repeatedly
+func Compile(schema json.RawMessage) (*Validator, error) {
+	loader := gojsonschema.NewSchemaLoader()
+	loader.Draft = gojsonschema.Draft7
+
+	compiled, err := loader.Compile(gojsonschema.NewBytesLoader(schema))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to compile schema")
+	}
+	return &Validator{schema: compiled}, nil
+}
+
+// Validate validates a value, which is encoded to JSON first. Errors are
+// located by the JSON pointer of the offending value, "" being the value
+// itself.
+func (v *Validator) Validate(value interface{}) (*core.Result, error) {
+	res, err := v.schema.Validate(gojsonschema.NewGoLoader(value))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to validate against schema")
+	}
+
+	result := &core.Result{Valid: res.Valid()}
+	for _, resErr := range res.Errors() {
+		path := pointer(resErr.Context())
+		if resErr.Type() == "required" {
+			// The error is reported on the object, point at the property
+			if property, ok := resErr.Details()["property"].(string); ok {
+				path += "/" + escapePointer(property)
+			}
+		}
+		result.Errors = append(result.Errors, core.Error{
+			RuleID:  resErr.Type(),
+			Message: resErr.Description(),
+			Path:    path,
+			Value:   resErr.Value(),
+		})
+	}
+	return result, nil
+}
+
+// ValidateJSON validates an encoded value
+func (v *Validator) ValidateJSON(data json.RawMessage) (*core.Result, error) {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, errors.Wrap(err, "failed to decode value")
+	}
+	return v.Validate(value)
+}
+
+// FormatErrors describes the errors of a result on a single line
+func FormatErrors(result *core.Result) string {
+	msgs := make([]string, 0, len(result.Errors))
+	for _, e := range result.Errors {
+		path := e.Path
+		if path == "" {
+			path = "/"
+		}
+		msgs = append(msgs, path+": "+e.Message)
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// pointer converts a gojsonschema context, like (root).a.0, into a JSON
+// pointer like /a/0
+func pointer(ctx *gojsonschema.JsonContext) string {
+	// Property names practically never contain NUL
+	parts := strings.Split(ctx.String("\x00"), "\x00")
+
+	var b strings.Builder
+	for _, part := range parts[1:] {
+		b.WriteString("/" + escapePointer(part))
+	}
+	return b.String()
+}
+
+// escapePointer escapes a JSON pointer token
+func escapePointer(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
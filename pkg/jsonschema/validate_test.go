@@ -0,0 +1,47 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidator(t *testing.T) {
+	v, err := Compile(json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string"},
+			"a/b": {"type": "integer"},
+			"items": {"type": "array", "items": {"type": "number"}}
+		},
+		"required": ["name"]
+	}`))
+	require.NoError(t, err)
+
+	result, err := v.ValidateJSON(json.RawMessage(`{"name":"x","items":[1,2]}`))
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Empty(t, result.Errors)
+
+	result, err = v.ValidateJSON(json.RawMessage(`{"a/b":"one","items":[1,"two"]}`))
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+
+	paths := make([]string, 0, len(result.Errors))
+	for _, e := range result.Errors {
+		paths = append(paths, e.Path)
+	}
+	assert.ElementsMatch(t, []string{"/name", "/a~1b", "/items/1"}, paths)
+	assert.Contains(t, FormatErrors(result), "/items/1: ")
+
+	result, err = v.Validate("not an object")
+	require.NoError(t, err)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, "", result.Errors[0].Path)
+	assert.Contains(t, FormatErrors(result), "/: ")
+
+	_, err = Compile(json.RawMessage(`{"type": 1}`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,123 @@
+// Package keepalive pings the peer of an MCP connection at an interval, to
+// detect peers that stopped answering and to measure round-trip times.
+package keepalive
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+)
+
+// DefaultMaxMissed is the number of consecutive pings a peer may miss
+// before it is considered dead, unless Options.MaxMissed says otherwise
+const DefaultMaxMissed = 3
+
+// ErrSkipped is returned by ping functions that could not reach the peer
+// for a reason that says nothing about its health, such as an HTTP client
+// without an open event stream. Skipped pings are not counted as missed.
+var ErrSkipped = errors.New("ping skipped")
+
+// Options configure keepalive pings
+type Options struct {
+	// Interval is the time between pings. Zero disables keepalive.
+	Interval time.Duration
+
+	// Timeout bounds how long a ping waits for its answer. Defaults to
+	// Interval.
+	Timeout time.Duration
+
+	// MaxMissed is the number of consecutive pings the peer may miss before
+	// it is considered dead. Defaults to DefaultMaxMissed.
+	MaxMissed int
+
+	// Metrics, if set, receives the round-trip times of the pings in the
+	// ping_rtt_seconds histogram
+	Metrics types.MetricsCollector
+}
+
+// Keeper runs keepalive loops with the same options
+type Keeper struct {
+	opts Options
+	rtt  types.Metric
+}
+
+// New creates a keeper, registering its metric with the collector of the
+// options if there is one
+func New(opts Options) (*Keeper, error) {
+	if opts.Timeout <= 0 {
+		opts.Timeout = opts.Interval
+	}
+	if opts.MaxMissed <= 0 {
+		opts.MaxMissed = DefaultMaxMissed
+	}
+
+	k := &Keeper{opts: opts}
+	if opts.Interval > 0 && opts.Metrics != nil {
+		rtt, err := opts.Metrics.NewMetric(types.MetricOpts{
+			Name:    "ping_rtt_seconds",
+			Help:    "Round-trip time of keepalive pings",
+			Type:    types.MetricTypeHistogram,
+			Buckets: []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create ping metric")
+		}
+		if err := opts.Metrics.Register(rtt); err != nil {
+			return nil, errors.Wrap(err, "failed to register ping metric")
+		}
+		k.rtt = rtt
+	}
+	return k, nil
+}
+
+// Enabled reports whether the keeper pings at all
+func (k *Keeper) Enabled() bool {
+	return k != nil && k.opts.Interval > 0
+}
+
+// Run calls ping every interval until ctx is done. Once MaxMissed
+// consecutive pings have failed, dead is called with the last error and Run
+// returns.
+func (k *Keeper) Run(ctx context.Context, ping func(ctx context.Context) error, dead func(err error)) {
+	if !k.Enabled() {
+		return
+	}
+
+	ticker := time.NewTicker(k.opts.Interval)
+	defer ticker.Stop()
+
+	missed := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		pingCtx, cancel := context.WithTimeout(ctx, k.opts.Timeout)
+		start := time.Now()
+		err := ping(pingCtx)
+		rtt := time.Since(start)
+		cancel()
+
+		switch {
+		case ctx.Err() != nil:
+			return
+		case errors.Is(err, ErrSkipped):
+		case err != nil:
+			missed++
+			if missed >= k.opts.MaxMissed {
+				dead(errors.Wrapf(err, "peer missed %d pings", missed))
+				return
+			}
+		default:
+			missed = 0
+			if k.rtt != nil {
+				k.rtt.Observe(rtt.Seconds())
+			}
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package keepalive
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+)
+
+// recordingMetric records the observed values
+type recordingMetric struct {
+	types.NoOpMetric
+	mu       sync.Mutex
+	observed []float64
+}
+
+func (m *recordingMetric) Observe(value float64, labels ...types.MetricLabel) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.observed = append(m.observed, value)
+}
+
+func (m *recordingMetric) count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.observed)
+}
+
+// recordingCollector hands out a single recording metric
+type recordingCollector struct {
+	types.NoOpMetricsCollector
+	metric *recordingMetric
+	opts   types.MetricOpts
+}
+
+func (c *recordingCollector) NewMetric(opts types.MetricOpts) (types.Metric, error) {
+	c.opts = opts
+	return c.metric, nil
+}
+
+func TestKeeper_Run(t *testing.T) {
+	collector := &recordingCollector{metric: &recordingMetric{}}
+	keeper, err := New(Options{Interval: time.Millisecond, MaxMissed: 2, Metrics: collector})
+	require.NoError(t, err)
+	assert.Equal(t, "ping_rtt_seconds", collector.opts.Name)
+	assert.Equal(t, types.MetricTypeHistogram, collector.opts.Type)
+
+	// Answers, then skips, misses once, answers again and finally misses
+	// twice in a row
+	results := []error{nil, ErrSkipped, errors.New("timeout"), nil, ErrSkipped, errors.New("timeout"), errors.New("gone")}
+	pings := 0
+	var deadErr error
+	keeper.Run(context.Background(), func(ctx context.Context) error {
+		_, ok := ctx.Deadline()
+		assert.True(t, ok)
+		err := results[pings]
+		pings++
+		return err
+	}, func(err error) {
+		deadErr = err
+	})
+
+	assert.Equal(t, len(results), pings)
+	require.Error(t, deadErr)
+	assert.Contains(t, deadErr.Error(), "peer missed 2 pings: gone")
+	assert.Equal(t, 2, collector.metric.count())
+}
+
+func TestKeeper_RunStops(t *testing.T) {
+	keeper, err := New(Options{Interval: time.Millisecond})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pinged := make(chan struct{}, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		keeper.Run(ctx, func(ctx context.Context) error {
+			select {
+			case pinged <- struct{}{}:
+			default:
+			}
+			return nil
+		}, func(err error) {
+			t.Error("healthy peer reported dead")
+		})
+	}()
+
+	<-pinged
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return once its context was done")
+	}
+
+	// A disabled keeper returns at once
+	disabled, err := New(Options{})
+	require.NoError(t, err)
+	assert.False(t, disabled.Enabled())
+	disabled.Run(context.Background(), func(ctx context.Context) error {
+		t.Fatal("disabled keeper pinged")
+		return nil
+	}, func(err error) {})
+}
@@ -1,7 +1,9 @@
 package metrics
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
 	"sync"
 	"time"
 
@@ -272,10 +274,19 @@ func (c *PrometheusCollector) NewTimer(name string, labels ...types.MetricLabel)
 	return &PrometheusTimer{start: time.Now()}
 }
 
+// Register registers metrics with the registry. Registering a metric that
+// is identical to a registered one is not an error: the metric then feeds
+// the registered one, so several components can share a registry.
 func (c *PrometheusCollector) Register(metrics ...types.Metric) error {
 	for _, m := range metrics {
 		if pm, ok := m.(*PrometheusMetric); ok {
 			if err := c.registry.Register(pm.collector); err != nil {
+				var already prometheus.AlreadyRegisteredError
+				if errors.As(err, &already) && reflect.TypeOf(already.ExistingCollector) == reflect.TypeOf(pm.vec) {
+					pm.collector = already.ExistingCollector
+					pm.vec = already.ExistingCollector
+					continue
+				}
 				return fmt.Errorf("failed to register metric: %w", err)
 			}
 		}
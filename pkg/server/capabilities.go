@@ -0,0 +1,56 @@
+package server
+
+import (
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+)
+
+// CapabilitiesFunc adjusts the capabilities computed by the server before
+// they are advertised to a client
+type CapabilitiesFunc func(caps types.ServerCapabilities) types.ServerCapabilities
+
+// Capabilities returns the capabilities the server advertises during
+// initialization. A capability is only advertised when a handler for it is
+// registered, so clients do not call methods the server cannot answer.
+// Logging is always available, see Session.Log.
+func (s *Server) Capabilities() types.ServerCapabilities {
+	s.mu.RLock()
+	caps := types.ServerCapabilities{Logging: &types.LoggingCapability{}}
+	if s.listToolsHandler != nil || s.listToolsPageHandler != nil || s.callToolHandler != nil {
+		caps.Tools = &types.ToolsCapability{}
+	}
+	if s.tools.len() > 0 {
+		// Changes to the registry are announced to the clients
+		caps.Tools = &types.ToolsCapability{ListChanged: true}
+	}
+	if s.listPromptsHandler != nil || s.listPromptsPageHandler != nil || s.getPromptHandler != nil {
+		caps.Prompts = &types.PromptsCapability{}
+	}
+	if s.listResourcesHandler != nil || s.listResourcesPageHandler != nil || s.readResourceHandler != nil ||
+		s.listResourceTemplatesHandler != nil || s.listResourceTemplatesPageHandler != nil {
+		// Clients can subscribe to any resource, see NotifyResourceUpdated
+		caps.Resources = &types.ResourcesCapability{Subscribe: true}
+	}
+	if s.resources.len() > 0 {
+		// Changes to the registry are announced to the clients
+		caps.Resources = &types.ResourcesCapability{Subscribe: true, ListChanged: true}
+	}
+	if len(s.completers) > 0 {
+		caps.Completions = &types.CompletionsCapability{}
+	}
+	hook := s.capabilitiesFunc
+	s.mu.RUnlock()
+
+	if hook != nil {
+		caps = hook(caps)
+	}
+	return caps
+}
+
+// capabilitiesFor removes the capabilities the protocol version does not
+// know about
+func capabilitiesFor(caps types.ServerCapabilities, version string) types.ServerCapabilities {
+	if !types.SupportsFeature(version, types.FeatureCompletions) {
+		caps.Completions = nil
+	}
+	return caps
+}
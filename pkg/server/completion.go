@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+)
+
+// Completer suggests values for an argument from its partial value.
+// Arguments holds the values of the other arguments the client already
+// resolved, so suggestions can depend on them. Completers may return any
+// number of values, the server sends the first types.MaxCompletionValues.
+type Completer func(ctx context.Context, value string, arguments map[string]string) ([]string, error)
+
+// completionKey identifies the argument of a prompt or the variable of a
+// resource template a completer is registered for
+type completionKey struct {
+	ref      types.CompletionReference
+	argument string
+}
+
+// AddPromptCompleter registers the completer of an argument of a prompt,
+// replacing any previous one
+func (s *Server) AddPromptCompleter(prompt, argument string, completer Completer) {
+	s.addCompleter(completionKey{ref: types.PromptReference(prompt), argument: argument}, completer)
+}
+
+// AddResourceCompleter registers the completer of a variable of a resource
+// template, identified by its URI template, replacing any previous one
+func (s *Server) AddResourceCompleter(uriTemplate, variable string, completer Completer) {
+	s.addCompleter(completionKey{ref: types.ResourceReference(uriTemplate), argument: variable}, completer)
+}
+
+func (s *Server) addCompleter(key completionKey, completer Completer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.completers == nil {
+		s.completers = make(map[completionKey]Completer)
+	}
+	s.completers[key] = completer
+}
+
+// Complete handles completion requests by passing them to the completer of
+// the argument. Arguments without a completer have no suggestions.
+func (s *Server) Complete(ctx context.Context, req *types.CompleteRequest) (*types.CompleteResult, error) {
+	key := completionKey{argument: req.Argument.Name}
+	switch req.Ref.Type {
+	case types.RefTypePrompt:
+		if req.Ref.Name == "" {
+			return nil, jsonrpc.NewError(jsonrpc.CodeInvalidParams, "prompt reference without name")
+		}
+		key.ref = types.PromptReference(req.Ref.Name)
+	case types.RefTypeResource:
+		if req.Ref.URI == "" {
+			return nil, jsonrpc.NewError(jsonrpc.CodeInvalidParams, "resource reference without uri")
+		}
+		key.ref = types.ResourceReference(req.Ref.URI)
+	default:
+		return nil, jsonrpc.NewError(jsonrpc.CodeInvalidParams, "unknown reference type: "+req.Ref.Type)
+	}
+
+	s.mu.RLock()
+	completer := s.completers[key]
+	s.mu.RUnlock()
+	if completer == nil {
+		return &types.CompleteResult{Completion: types.Completion{Values: []string{}}}, nil
+	}
+
+	var arguments map[string]string
+	if req.Context != nil {
+		arguments = req.Context.Arguments
+	}
+	values, err := completer(ctx, req.Argument.Value, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	completion := types.Completion{Values: values}
+	if completion.Values == nil {
+		completion.Values = []string{}
+	}
+	if len(values) > types.MaxCompletionValues {
+		completion.Values = values[:types.MaxCompletionValues]
+		completion.Total = len(values)
+		completion.HasMore = true
+	}
+	return &types.CompleteResult{Completion: completion}, nil
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+)
+
+func TestServer_Complete(t *testing.T) {
+	srv, d := newTestDispatcher(t)
+
+	languages := []string{"go", "python", "rust"}
+	srv.AddPromptCompleter("review", "language", func(ctx context.Context, value string, arguments map[string]string) ([]string, error) {
+		var values []string
+		for _, language := range languages {
+			if strings.HasPrefix(language, value) {
+				values = append(values, language)
+			}
+		}
+		return values, nil
+	})
+	srv.AddPromptCompleter("review", "framework", func(ctx context.Context, value string, arguments map[string]string) ([]string, error) {
+		if arguments["language"] == "go" {
+			return []string{"gin", "echo"}, nil
+		}
+		return nil, nil
+	})
+	srv.AddResourceCompleter("file:///logs/{day}", "day", func(ctx context.Context, value string, arguments map[string]string) ([]string, error) {
+		values := make([]string, 150)
+		for i := range values {
+			values[i] = fmt.Sprintf("%s%03d", value, i)
+		}
+		return values, nil
+	})
+	assert.NotNil(t, srv.Capabilities().Completions)
+
+	ctx := initialize(t, d)
+
+	tests := []struct {
+		name     string
+		params   string
+		expected types.Completion
+		code     int
+	}{
+		{
+			name:     "prompt argument",
+			params:   `{"ref":{"type":"ref/prompt","name":"review"},"argument":{"name":"language","value":"py"}}`,
+			expected: types.Completion{Values: []string{"python"}},
+		},
+		{
+			name:     "resolved arguments",
+			params:   `{"ref":{"type":"ref/prompt","name":"review"},"argument":{"name":"framework","value":""},"context":{"arguments":{"language":"go"}}}`,
+			expected: types.Completion{Values: []string{"gin", "echo"}},
+		},
+		{
+			name:     "no values",
+			params:   `{"ref":{"type":"ref/prompt","name":"review"},"argument":{"name":"framework","value":""}}`,
+			expected: types.Completion{Values: []string{}},
+		},
+		{
+			name:     "argument without completer",
+			params:   `{"ref":{"type":"ref/prompt","name":"other"},"argument":{"name":"language","value":"g"}}`,
+			expected: types.Completion{Values: []string{}},
+		},
+		{
+			name:   "unknown reference type",
+			params: `{"ref":{"type":"ref/tool","name":"review"},"argument":{"name":"language","value":"g"}}`,
+			code:   jsonrpc.CodeInvalidParams,
+		},
+		{
+			name:   "prompt reference without name",
+			params: `{"ref":{"type":"ref/prompt"},"argument":{"name":"language","value":"g"}}`,
+			code:   jsonrpc.CodeInvalidParams,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":1,"method":"completion/complete","params":`+tt.params+`}`)
+			if tt.code != 0 {
+				require.NotNil(t, resp.Error)
+				assert.Equal(t, tt.code, resp.Error.Code)
+				return
+			}
+			require.Nil(t, resp.Error)
+
+			var result types.CompleteResult
+			require.NoError(t, json.Unmarshal(resp.Result, &result))
+			assert.Equal(t, tt.expected, result.Completion)
+		})
+	}
+
+	t.Run("capped values", func(t *testing.T) {
+		resp := dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":1,"method":"completion/complete","params":{"ref":{"type":"ref/resource","uri":"file:///logs/{day}"},"argument":{"name":"day","value":"d"}}}`)
+		require.Nil(t, resp.Error)
+
+		var result types.CompleteResult
+		require.NoError(t, json.Unmarshal(resp.Result, &result))
+		assert.Len(t, result.Completion.Values, types.MaxCompletionValues)
+		assert.Equal(t, "d000", result.Completion.Values[0])
+		assert.Equal(t, 150, result.Completion.Total)
+		assert.True(t, result.Completion.HasMore)
+	})
+}
+
+func TestServer_CompleteProtocolVersion(t *testing.T) {
+	srv, d := newTestDispatcher(t)
+	srv.AddPromptCompleter("review", "language", func(ctx context.Context, value string, arguments map[string]string) ([]string, error) {
+		return []string{"go"}, nil
+	})
+
+	ctx := WithConnection(context.Background(), NewConnection())
+	resp := dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":"init","method":"initialize","params":{"protocolVersion":"2024-11-05"}}`)
+	require.Nil(t, resp.Error)
+	var init types.InitializeResponse
+	require.NoError(t, json.Unmarshal(resp.Result, &init))
+	assert.Nil(t, init.Capabilities.Completions)
+	require.Nil(t, dispatch(t, ctx, d, `{"jsonrpc":"2.0","method":"notifications/initialized"}`))
+
+	resp = dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":1,"method":"completion/complete","params":{"ref":{"type":"ref/prompt","name":"review"},"argument":{"name":"language","value":""}}}`)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, jsonrpc.CodeMethodNotFound, resp.Error.Code)
+}
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"context"
+	"sync"
+)
+
+// Connection binds a transport connection to its session. Stream transports
+// keep one per connection, HTTP creates one per request from the
+// Mcp-Session-Id header. The dispatcher finds it in the request context, see
+// WithConnection.
+type Connection struct {
+	mu       sync.RWMutex
+	session  *Session
+	notifier Notifier
+}
+
+// NewConnection creates a connection that is not bound to a session yet
+func NewConnection() *Connection {
+	return &Connection{}
+}
+
+// SetNotifier sets how notifications reach the client at the other end of
+// the connection. The session bound to the connection uses it.
+func (c *Connection) SetNotifier(notifier Notifier) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.notifier = notifier
+	if c.session != nil {
+		c.session.setNotifier(notifier)
+	}
+}
+
+// Session returns the session bound to the connection, or nil before
+// initialize succeeded and once the session was closed
+func (c *Connection) Session() *Session {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.session == nil || c.session.IsClosed() {
+		return nil
+	}
+	return c.session
+}
+
+// Bind binds the connection to an existing session. It reports false when
+// the connection is already bound to another open session.
+func (c *Connection) Bind(session *Session) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.session != nil && c.session != session && !c.session.IsClosed() {
+		return false
+	}
+	c.session = session
+	if c.notifier != nil {
+		session.setNotifier(c.notifier)
+	}
+	return true
+}
+
+// connectionKey is the context key of the connection
+type connectionKey struct{}
+
+// WithConnection returns a context carrying the connection, which transports
+// pass to the dispatcher for every message received on it
+func WithConnection(ctx context.Context, conn *Connection) context.Context {
+	return context.WithValue(ctx, connectionKey{}, conn)
+}
+
+// connectionFromContext returns the connection carried by ctx, if any
+func connectionFromContext(ctx context.Context) *Connection {
+	conn, _ := ctx.Value(connectionKey{}).(*Connection)
+	return conn
+}
+
+// SessionFromContext returns the session the request being handled belongs
+// to. Handlers use it to query the negotiated protocol version and the
+// client capabilities.
+func SessionFromContext(ctx context.Context) (*Session, bool) {
+	conn := connectionFromContext(ctx)
+	if conn == nil {
+		return nil, false
+	}
+	session := conn.Session()
+	return session, session != nil
+}
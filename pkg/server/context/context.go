@@ -7,14 +7,14 @@ import (
 	"sync"
 	"time"
 
-	"github.com/harriteja/mcp-go-sdk/pkg/server/session"
+	"github.com/harriteja/mcp-go-sdk/pkg/server"
 	"github.com/harriteja/mcp-go-sdk/pkg/types"
 )
 
 // Context represents the context for MCP operations
 type Context struct {
 	ctx       context.Context
-	session   *session.ServerSession
+	session   *server.Session
 	requestID string
 	clientID  string
 	progress  *progressInfo
@@ -29,7 +29,7 @@ type progressInfo struct {
 }
 
 // NewContext creates a new MCP context
-func NewContext(ctx context.Context, sess *session.ServerSession, requestID, clientID string) *Context {
+func NewContext(ctx context.Context, sess *server.Session, requestID, clientID string) *Context {
 	return &Context{
 		ctx:       ctx,
 		session:   sess,
@@ -46,7 +46,7 @@ func (c *Context) Context() context.Context {
 }
 
 // Session returns the server session
-func (c *Context) Session() *session.ServerSession {
+func (c *Context) Session() *server.Session {
 	return c.session
 }
 
@@ -60,7 +60,8 @@ func (c *Context) ClientID() string {
 	return c.clientID
 }
 
-// ReportProgress reports the current progress
+// ReportProgress reports the current progress. When the client asked for
+// the progress of the request, it is sent as notifications/progress.
 func (c *Context) ReportProgress(current float64, total *float64) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -79,16 +80,16 @@ func (c *Context) ReportProgress(current float64, total *float64) error {
 	}
 
 	// Update the tracker
-	if err := c.tracker.Update(percentage, fmt.Sprintf("Progress: %.2f", current)); err != nil {
+	message := fmt.Sprintf("Progress: %.2f", current)
+	if err := c.tracker.Update(percentage, message); err != nil {
 		return err
 	}
 
-	// Write progress to stream if available
-	if c.stream != nil {
-		return c.stream.Writer().WriteProgress(c.tracker.Current())
+	var totalValue float64
+	if total != nil {
+		totalValue = *total
 	}
-
-	return nil
+	return server.ReportProgress(c.ctx, current, totalValue, message)
 }
 
 // GetProgress returns the current progress
@@ -149,25 +150,34 @@ func (c *Context) ReadResource(uri string) (io.ReadCloser, string, error) {
 	return nil, "", fmt.Errorf("not implemented")
 }
 
-// Info logs an informational message
+// Info logs an informational message to the client, see server.Log
 func (c *Context) Info(format string, args ...interface{}) {
-	// Implementation depends on how we want to handle logging
-	// This could be through the session, a logger, or other mechanisms
+	c.log(types.LoggingLevelInfo, format, args...)
 }
 
-// Debug logs a debug message
+// Debug logs a debug message to the client
 func (c *Context) Debug(format string, args ...interface{}) {
-	// Implementation depends on how we want to handle logging
+	c.log(types.LoggingLevelDebug, format, args...)
 }
 
-// Warning logs a warning message
+// Warning logs a warning message to the client
 func (c *Context) Warning(format string, args ...interface{}) {
-	// Implementation depends on how we want to handle logging
+	c.log(types.LoggingLevelWarning, format, args...)
 }
 
-// Error logs an error message
+// Error logs an error message to the client
 func (c *Context) Error(format string, args ...interface{}) {
-	// Implementation depends on how we want to handle logging
+	c.log(types.LoggingLevelError, format, args...)
+}
+
+// log sends a message to the client of the session, if it asked for
+// messages of the level
+func (c *Context) log(level types.LoggingLevel, format string, args ...interface{}) {
+	if c.session == nil {
+		return
+	}
+	// Logging has no way to report its own failures
+	_ = c.session.Log(c.ctx, level, "", fmt.Sprintf(format, args...))
 }
 
 // WithValue returns a new Context with the provided key-value pair
@@ -204,18 +214,14 @@ func (c *Context) Deadline() (deadline time.Time, ok bool) {
 	return c.ctx.Deadline()
 }
 
-// StartProgress starts progress tracking
+// StartProgress starts progress tracking. Progress tracked by the Context
+// is sent as a percentage with notifications/progress when the client asked
+// for the progress of the request.
 func (c *Context) StartProgress(message string) error {
 	if err := c.tracker.Start(message); err != nil {
 		return err
 	}
-
-	// Write initial progress to stream if available
-	if c.stream != nil {
-		return c.stream.Writer().WriteProgress(c.tracker.Current())
-	}
-
-	return nil
+	return c.sendProgress()
 }
 
 // UpdateProgress updates the progress state
@@ -223,13 +229,7 @@ func (c *Context) UpdateProgress(percentage float64, message string) error {
 	if err := c.tracker.Update(percentage, message); err != nil {
 		return err
 	}
-
-	// Write progress to stream if available
-	if c.stream != nil {
-		return c.stream.Writer().WriteProgress(c.tracker.Current())
-	}
-
-	return nil
+	return c.sendProgress()
 }
 
 // CompleteProgress marks the progress as completed
@@ -237,27 +237,19 @@ func (c *Context) CompleteProgress(message string) error {
 	if err := c.tracker.Complete(message); err != nil {
 		return err
 	}
-
-	// Write final progress to stream if available
-	if c.stream != nil {
-		return c.stream.Writer().WriteProgress(c.tracker.Current())
-	}
-
-	return nil
+	return c.sendProgress()
 }
 
-// FailProgress marks the progress as failed
+// FailProgress marks the progress as failed. The failure itself reaches the
+// client with the response, so nothing is sent.
 func (c *Context) FailProgress(err error) error {
-	if err := c.tracker.Fail(err); err != nil {
-		return err
-	}
-
-	// Write failed progress to stream if available
-	if c.stream != nil {
-		return c.stream.Writer().WriteProgress(c.tracker.Current())
-	}
+	return c.tracker.Fail(err)
+}
 
-	return nil
+// sendProgress sends the state of the tracker to the client
+func (c *Context) sendProgress() error {
+	progress := c.tracker.Current()
+	return server.ReportProgress(c.ctx, progress.Percentage, 100, progress.Message)
 }
 
 // Progress returns the current progress state
@@ -0,0 +1,569 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+)
+
+// requestHandler handles a request and returns its result
+type requestHandler func(ctx context.Context, msg *jsonrpc.Message) (interface{}, error)
+
+// notificationHandler handles a notification, which never gets a response
+type notificationHandler func(ctx context.Context, msg *jsonrpc.Message) error
+
+// Dispatcher decodes JSON-RPC messages, routes them to a Server and encodes
+// the replies. Transports only deal with framing and hand every message to
+// Handle, so a method added here behaves identically on all of them.
+type Dispatcher struct {
+	server        *Server
+	logger        types.Logger
+	requests      map[string]requestHandler
+	notifications map[string]notificationHandler
+
+	// wg tracks requests started by HandleAsync
+	wg sync.WaitGroup
+
+	// legacyIDs numbers the requests sent in the legacy envelope
+	legacyIDs int64
+}
+
+// NewDispatcher creates a new dispatcher for the server
+func NewDispatcher(srv *Server) *Dispatcher {
+	d := &Dispatcher{
+		server: srv,
+		logger: srv.logger,
+	}
+
+	d.requests = map[string]requestHandler{
+		types.MethodInitialize:             d.initialize,
+		types.MethodPing:                   d.ping,
+		types.MethodToolsList:              d.listTools,
+		types.MethodToolsCall:              d.callTool,
+		types.MethodPromptsList:            d.listPrompts,
+		types.MethodPromptsGet:             d.getPrompt,
+		types.MethodResourcesList:          d.listResources,
+		types.MethodResourcesRead:          d.readResource,
+		types.MethodResourcesTemplatesList: d.listResourceTemplates,
+		types.MethodResourcesSubscribe:     d.subscribe,
+		types.MethodResourcesUnsubscribe:   d.unsubscribe,
+		types.MethodCompletionComplete:     d.complete,
+		types.MethodLoggingSetLevel:        d.setLevel,
+	}
+	d.notifications = map[string]notificationHandler{
+		types.NotificationInitialized: d.initialized,
+		types.NotificationCancelled:   d.cancel,
+
+		types.NotificationRootsListChanged: d.rootsListChanged,
+	}
+
+	if srv.legacyMethodNames {
+		d.registerLegacyMethods()
+	}
+
+	return d
+}
+
+// Handle processes a single encoded message and returns the encoded
+// response. It returns nil when no response is due, which is the case for
+// notifications, for responses sent by the client and for requests the
+// client cancelled.
+func (d *Dispatcher) Handle(ctx context.Context, data []byte) ([]byte, error) {
+	if msg, ok := d.decodeLegacy(data); ok {
+		return d.handleLegacy(ctx, msg)
+	}
+	msg, errResp := d.decode(ctx, data)
+	if msg == nil {
+		return errResp, nil
+	}
+	return d.encode(d.HandleMessage(ctx, msg))
+}
+
+// HandleAsync processes a single encoded message like Handle, but runs
+// requests in their own goroutine and passes their encoded response to
+// reply. Everything else is handled before HandleAsync returns, so a
+// cancellation is never processed ahead of the request it refers to. reply
+// may be called concurrently.
+func (d *Dispatcher) HandleAsync(ctx context.Context, data []byte, reply func([]byte)) {
+	if msg, ok := d.decodeLegacy(data); ok {
+		// Legacy responses carry no ID, so they are sent in request order
+		out, err := d.handleLegacy(ctx, msg)
+		if err != nil {
+			d.logger.Error(ctx, "dispatcher", msg.Method, "Failed to encode response: "+err.Error())
+			return
+		}
+		if out != nil {
+			reply(out)
+		}
+		return
+	}
+	msg, errResp := d.decode(ctx, data)
+	if msg == nil {
+		if errResp != nil {
+			reply(errResp)
+		}
+		return
+	}
+	if !msg.IsRequest() {
+		d.HandleMessage(ctx, msg)
+		return
+	}
+
+	reqCtx, done := d.server.inflight.track(ctx, msg.RequestID())
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		defer done()
+
+		out, err := d.encode(d.handleRequest(reqCtx, msg))
+		if err != nil {
+			d.logger.Error(ctx, "dispatcher", msg.Method, "Failed to encode response: "+err.Error())
+			return
+		}
+		if out != nil {
+			reply(out)
+		}
+	}()
+}
+
+// Wait blocks until every request started by HandleAsync has completed
+func (d *Dispatcher) Wait() {
+	d.wg.Wait()
+}
+
+// HandleMessage processes a decoded message and returns the response, or nil
+// when no response is due
+func (d *Dispatcher) HandleMessage(ctx context.Context, msg *jsonrpc.Message) *jsonrpc.Response {
+	switch {
+	case msg.IsNotification():
+		d.handleNotification(ctx, msg)
+		return nil
+	case msg.IsRequest():
+		ctx, done := d.server.inflight.track(ctx, msg.RequestID())
+		defer done()
+		return d.handleRequest(ctx, msg)
+	default:
+		// Responses answer the requests the server sent to the client
+		if session, ok := SessionFromContext(ctx); ok && session.resolve(msg.Response()) {
+			return nil
+		}
+		d.logger.Warn(ctx, "dispatcher", "response", "Ignoring unexpected response for request "+msg.RequestID().String())
+		return nil
+	}
+}
+
+// decode decodes a message. When the data is not a valid message it returns
+// a nil message along with the encoded error response, if one is due.
+func (d *Dispatcher) decode(ctx context.Context, data []byte) (*jsonrpc.Message, []byte) {
+	msg, rpcErr := jsonrpc.Decode(data)
+	if rpcErr == nil {
+		return msg, nil
+	}
+
+	d.logger.Error(ctx, "dispatcher", "decode", "Invalid message: "+rpcErr.Message)
+	if msg != nil && msg.IsNotification() {
+		return nil, nil
+	}
+	var id jsonrpc.ID
+	if msg != nil {
+		id = msg.RequestID()
+	}
+	out, err := json.Marshal(jsonrpc.NewErrorResponse(id, rpcErr))
+	if err != nil {
+		d.logger.Error(ctx, "dispatcher", "decode", "Failed to encode error response: "+err.Error())
+		return nil, nil
+	}
+	return nil, out
+}
+
+// encode encodes a response, returning nil when there is none
+func (d *Dispatcher) encode(resp *jsonrpc.Response) ([]byte, error) {
+	if resp == nil {
+		return nil, nil
+	}
+	return json.Marshal(resp)
+}
+
+// handleRequest invokes the handler registered for the request method
+func (d *Dispatcher) handleRequest(ctx context.Context, msg *jsonrpc.Message) *jsonrpc.Response {
+	id := msg.RequestID()
+
+	handler, ok := d.requests[msg.Method]
+	if !ok {
+		d.logger.Error(ctx, "dispatcher", "request", "Unknown method: "+msg.Method)
+		return jsonrpc.NewErrorResponse(id, jsonrpc.NewError(jsonrpc.CodeMethodNotFound, "method not found: "+msg.Method))
+	}
+
+	// Only initialize and ping may be sent before the session exists
+	session, ok := SessionFromContext(ctx)
+	if ok {
+		session.touch()
+	} else if msg.Method != types.MethodInitialize && msg.Method != types.MethodPing {
+		d.logger.Warn(ctx, "dispatcher", msg.Method, "Rejecting request before initialization")
+		return jsonrpc.NewErrorResponse(id, jsonrpc.NewError(jsonrpc.CodeInvalidRequest, "server not initialized"))
+	}
+
+	result, err := handler(withProgress(ctx, msg), msg)
+	if isCancelled(ctx) {
+		// The client is no longer waiting for this response
+		d.logger.Info(ctx, "dispatcher", msg.Method, "Request "+id.String()+" cancelled, dropping response")
+		return nil
+	}
+	if err != nil {
+		d.logger.Error(ctx, "dispatcher", msg.Method, "Request failed: "+err.Error())
+		return jsonrpc.NewErrorResponse(id, jsonrpc.ErrorFrom(err))
+	}
+
+	resp, err := jsonrpc.NewResponse(id, result)
+	if err != nil {
+		d.logger.Error(ctx, "dispatcher", msg.Method, "Failed to encode result: "+err.Error())
+		return jsonrpc.NewErrorResponse(id, jsonrpc.NewError(jsonrpc.CodeInternalError, err.Error()))
+	}
+	return resp
+}
+
+// handleNotification invokes the handler registered for the notification
+// method. Failures are only logged because notifications are never answered.
+func (d *Dispatcher) handleNotification(ctx context.Context, msg *jsonrpc.Message) {
+	handler, ok := d.notifications[msg.Method]
+	if !ok {
+		d.logger.Warn(ctx, "dispatcher", "notification", "Unknown notification: "+msg.Method)
+		return
+	}
+
+	if session, ok := SessionFromContext(ctx); ok {
+		session.touch()
+	}
+	if err := handler(ctx, msg); err != nil {
+		d.logger.Error(ctx, "dispatcher", msg.Method, "Notification failed: "+err.Error())
+	}
+}
+
+// supports reports whether the session of ctx negotiated a protocol version
+// with the feature. Requests without a session are answered in the latest
+// version.
+func supports(ctx context.Context, feature types.Feature) bool {
+	if session, ok := SessionFromContext(ctx); ok {
+		return session.Supports(feature)
+	}
+	return true
+}
+
+// downgradeContent replaces a content block the session of ctx cannot
+// receive with text describing it: audio needs protocol version 2025-03-26
+// and resource links 2025-06-18. It reports whether c was replaced.
+func downgradeContent(ctx context.Context, c types.Content) (types.Content, bool) {
+	switch block := c.(type) {
+	case *types.AudioContent:
+		if block != nil {
+			c = *block
+		}
+	case *types.ResourceLink:
+		if block != nil {
+			c = *block
+		}
+	}
+
+	switch block := c.(type) {
+	case types.AudioContent:
+		if !supports(ctx, types.FeatureAudioContent) {
+			return types.NewTextContent("[" + block.MimeType + " audio not supported by this protocol version]"), true
+		}
+	case types.ResourceLink:
+		if !supports(ctx, types.FeatureResourceLinks) {
+			return types.NewTextContent("Resource " + block.Name + ": " + block.URI), true
+		}
+	}
+	return c, false
+}
+
+// downgradeContents applies downgradeContent to a list of content blocks.
+// It returns a copy of the list if any block was replaced, and nil
+// otherwise.
+func downgradeContents(ctx context.Context, contents []types.Content) []types.Content {
+	var downgraded []types.Content
+	for i, c := range contents {
+		replacement, ok := downgradeContent(ctx, c)
+		if !ok {
+			continue
+		}
+		if downgraded == nil {
+			downgraded = append([]types.Content(nil), contents...)
+		}
+		downgraded[i] = replacement
+	}
+	return downgraded
+}
+
+// unmarshalParams decodes request params, returning a JSON-RPC error that can
+// be sent back as is
+func unmarshalParams(msg *jsonrpc.Message, v interface{}) error {
+	if rpcErr := msg.UnmarshalParams(v); rpcErr != nil {
+		return rpcErr
+	}
+	return nil
+}
+
+func (d *Dispatcher) initialize(ctx context.Context, msg *jsonrpc.Message) (interface{}, error) {
+	var params types.InitializeRequest
+	if err := unmarshalParams(msg, &params); err != nil {
+		return nil, err
+	}
+	return d.server.Initialize(ctx, &params)
+}
+
+func (d *Dispatcher) initialized(ctx context.Context, msg *jsonrpc.Message) error {
+	var notification types.InitializedNotification
+	if err := unmarshalParams(msg, &notification); err != nil {
+		return err
+	}
+	return d.server.Initialized(ctx, &notification)
+}
+
+func (d *Dispatcher) ping(ctx context.Context, msg *jsonrpc.Message) (interface{}, error) {
+	var params types.PingRequest
+	if err := unmarshalParams(msg, &params); err != nil {
+		return nil, err
+	}
+	return d.server.Ping(ctx, &params)
+}
+
+func (d *Dispatcher) cancel(ctx context.Context, msg *jsonrpc.Message) error {
+	var params types.CancelledNotification
+	if err := unmarshalParams(msg, &params); err != nil {
+		return err
+	}
+	return d.server.Cancel(ctx, &params)
+}
+
+func (d *Dispatcher) rootsListChanged(ctx context.Context, msg *jsonrpc.Message) error {
+	var params types.RootsListChangedNotification
+	if err := unmarshalParams(msg, &params); err != nil {
+		return err
+	}
+	return d.server.RootsListChanged(ctx, &params)
+}
+
+func (d *Dispatcher) listTools(ctx context.Context, msg *jsonrpc.Message) (interface{}, error) {
+	var params types.PaginatedRequest
+	if err := unmarshalParams(msg, &params); err != nil {
+		return nil, err
+	}
+	tools, next, err := d.server.ListToolsPage(ctx, params.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	if tools == nil {
+		tools = []types.Tool{}
+	}
+	// Output schemas came with structured content
+	outputSchemas := supports(ctx, types.FeatureStructuredContent)
+	annotations := supports(ctx, types.FeatureToolAnnotations)
+	if !outputSchemas || !annotations {
+		stripped := make([]types.Tool, len(tools))
+		for i, tool := range tools {
+			if !outputSchemas {
+				tool.OutputSchema = nil
+			}
+			if !annotations {
+				tool.Annotations = nil
+			}
+			stripped[i] = tool
+		}
+		tools = stripped
+	}
+	return &types.ListToolsResult{Tools: tools, NextCursor: next}, nil
+}
+
+func (d *Dispatcher) callTool(ctx context.Context, msg *jsonrpc.Message) (interface{}, error) {
+	var params types.CallToolRequest
+	if err := unmarshalParams(msg, &params); err != nil {
+		return nil, err
+	}
+	value, err := d.server.CallTool(ctx, params.Name, params.Arguments)
+	if err != nil {
+		return nil, err
+	}
+	result, err := toolResult(value)
+	if err != nil {
+		return nil, err
+	}
+	if result.StructuredContent != nil && !supports(ctx, types.FeatureStructuredContent) {
+		// The text content carries the same value for older clients
+		stripped := *result
+		stripped.StructuredContent = nil
+		result = &stripped
+	}
+	if content := downgradeContents(ctx, result.Content); content != nil {
+		downgraded := *result
+		downgraded.Content = content
+		result = &downgraded
+	}
+	return result, nil
+}
+
+func (d *Dispatcher) listPrompts(ctx context.Context, msg *jsonrpc.Message) (interface{}, error) {
+	var params types.PaginatedRequest
+	if err := unmarshalParams(msg, &params); err != nil {
+		return nil, err
+	}
+	prompts, next, err := d.server.ListPromptsPage(ctx, params.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	if prompts == nil {
+		prompts = []types.Prompt{}
+	}
+	return &types.ListPromptsResult{Prompts: prompts, NextCursor: next}, nil
+}
+
+func (d *Dispatcher) getPrompt(ctx context.Context, msg *jsonrpc.Message) (interface{}, error) {
+	var params types.GetPromptRequest
+	if err := unmarshalParams(msg, &params); err != nil {
+		return nil, err
+	}
+	result, err := d.server.GetPrompt(ctx, params.Name, params.Arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []types.PromptMessage
+	for i, msg := range result.Messages {
+		content, ok := downgradeContent(ctx, msg.Content)
+		if !ok {
+			continue
+		}
+		if messages == nil {
+			messages = append([]types.PromptMessage(nil), result.Messages...)
+		}
+		messages[i].Content = content
+	}
+	if messages != nil {
+		downgraded := *result
+		downgraded.Messages = messages
+		result = &downgraded
+	}
+	return result, nil
+}
+
+func (d *Dispatcher) listResources(ctx context.Context, msg *jsonrpc.Message) (interface{}, error) {
+	var params types.PaginatedRequest
+	if err := unmarshalParams(msg, &params); err != nil {
+		return nil, err
+	}
+	resources, next, err := d.server.ListResourcesPage(ctx, params.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	if resources == nil {
+		resources = []types.Resource{}
+	}
+	return &types.ListResourcesResult{Resources: resources, NextCursor: next}, nil
+}
+
+func (d *Dispatcher) readResource(ctx context.Context, msg *jsonrpc.Message) (interface{}, error) {
+	var params types.ReadResourceRequest
+	if err := unmarshalParams(msg, &params); err != nil {
+		return nil, err
+	}
+	data, mimeType, err := d.server.ReadResource(ctx, params.URI)
+	if err != nil {
+		return nil, err
+	}
+	return &types.ReadResourceResult{
+		Contents: []types.ResourceContents{types.NewResourceContents(params.URI, data, mimeType)},
+	}, nil
+}
+
+func (d *Dispatcher) subscribe(ctx context.Context, msg *jsonrpc.Message) (interface{}, error) {
+	var params types.SubscribeRequest
+	if err := unmarshalParams(msg, &params); err != nil {
+		return nil, err
+	}
+	if err := d.server.SubscribeResource(ctx, params.URI); err != nil {
+		return nil, err
+	}
+	return struct{}{}, nil
+}
+
+func (d *Dispatcher) unsubscribe(ctx context.Context, msg *jsonrpc.Message) (interface{}, error) {
+	var params types.UnsubscribeRequest
+	if err := unmarshalParams(msg, &params); err != nil {
+		return nil, err
+	}
+	if err := d.server.UnsubscribeResource(ctx, params.URI); err != nil {
+		return nil, err
+	}
+	return struct{}{}, nil
+}
+
+func (d *Dispatcher) setLevel(ctx context.Context, msg *jsonrpc.Message) (interface{}, error) {
+	var params types.SetLevelRequest
+	if err := unmarshalParams(msg, &params); err != nil {
+		return nil, err
+	}
+	if err := d.server.SetLogLevel(ctx, &params); err != nil {
+		return nil, err
+	}
+	return struct{}{}, nil
+}
+
+func (d *Dispatcher) complete(ctx context.Context, msg *jsonrpc.Message) (interface{}, error) {
+	if !supports(ctx, types.FeatureCompletions) {
+		return nil, jsonrpc.NewError(jsonrpc.CodeMethodNotFound, "method not found: "+msg.Method)
+	}
+	var params types.CompleteRequest
+	if err := unmarshalParams(msg, &params); err != nil {
+		return nil, err
+	}
+	return d.server.Complete(ctx, &params)
+}
+
+func (d *Dispatcher) listResourceTemplates(ctx context.Context, msg *jsonrpc.Message) (interface{}, error) {
+	var params types.PaginatedRequest
+	if err := unmarshalParams(msg, &params); err != nil {
+		return nil, err
+	}
+	templates, next, err := d.server.ListResourceTemplatesPage(ctx, params.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	if templates == nil {
+		templates = []types.ResourceTemplate{}
+	}
+	return &types.ListResourceTemplatesResult{ResourceTemplates: templates, NextCursor: next}, nil
+}
+
+// toolResult wraps the value returned by a tool handler into a tools/call
+// result. Results and content blocks are used as is, strings become text
+// content and other values are encoded as JSON text.
+func toolResult(v interface{}) (*types.CallToolResult, error) {
+	switch v := v.(type) {
+	case nil:
+		return &types.CallToolResult{Content: []types.Content{}}, nil
+	case *types.CallToolResult:
+		if v.Content == nil {
+			v.Content = []types.Content{}
+		}
+		return v, nil
+	case types.CallToolResult:
+		return toolResult(&v)
+	case types.Content:
+		return &types.CallToolResult{Content: []types.Content{v}}, nil
+	case []types.Content:
+		return &types.CallToolResult{Content: v}, nil
+	case string:
+		return types.NewTextToolResult(v), nil
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to encode tool result")
+		}
+		return types.NewTextToolResult(string(data)), nil
+	}
+}
@@ -0,0 +1,316 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+)
+
+func newTestDispatcher(t *testing.T) (*Server, *Dispatcher) {
+	return newTestDispatcherWithOptions(t, &Options{})
+}
+
+func newTestDispatcherWithOptions(t *testing.T, opts *Options) (*Server, *Dispatcher) {
+	opts.Name = "test-server"
+	opts.Version = "1.0.0"
+	opts.Logger = types.NewNoOpLogger()
+	srv, err := New(opts)
+	require.NoError(t, err)
+	return srv, NewDispatcher(srv)
+}
+
+// initialize returns a context bound to a new connection on which the
+// initialization handshake has completed
+func initialize(t *testing.T, d *Dispatcher) context.Context {
+	ctx := WithConnection(context.Background(), NewConnection())
+	resp := dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":"init","method":"initialize","params":{"protocolVersion":"2025-06-18"}}`)
+	require.Nil(t, resp.Error)
+	require.Nil(t, dispatch(t, ctx, d, `{"jsonrpc":"2.0","method":"notifications/initialized"}`))
+	return ctx
+}
+
+func dispatch(t *testing.T, ctx context.Context, d *Dispatcher, data string) *jsonrpc.Response {
+	out, err := d.Handle(ctx, []byte(data))
+	require.NoError(t, err)
+	if out == nil {
+		return nil
+	}
+
+	var resp jsonrpc.Response
+	require.NoError(t, json.Unmarshal(out, &resp))
+	return &resp
+}
+
+func TestDispatcher_Routing(t *testing.T) {
+	srv, d := newTestDispatcher(t)
+	ctx := initialize(t, d)
+
+	srv.OnCallTool(func(ctx context.Context, name string, args map[string]interface{}) (interface{}, error) {
+		return map[string]interface{}{"name": name, "value": args["value"]}, nil
+	})
+	srv.OnReadResource(func(ctx context.Context, uri string) ([]byte, string, error) {
+		return []byte("hello"), "text/plain", nil
+	})
+	srv.OnListResourceTemplates(func(ctx context.Context) ([]types.ResourceTemplate, error) {
+		return []types.ResourceTemplate{{URITemplate: "file:///{path}", Name: "files"}}, nil
+	})
+
+	resp := dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo","arguments":{"value":"x"}}}`)
+	require.Nil(t, resp.Error)
+	assert.Equal(t, "1", resp.ID.String())
+	assert.JSONEq(t, `{"content":[{"type":"text","text":"{\"name\":\"echo\",\"value\":\"x\"}"}]}`, string(resp.Result))
+
+	resp = dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":2,"method":"resources/read","params":{"uri":"file:///a"}}`)
+	require.Nil(t, resp.Error)
+	assert.JSONEq(t, `{"contents":[{"uri":"file:///a","mimeType":"text/plain","text":"hello"}]}`, string(resp.Result))
+
+	resp = dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":3,"method":"resources/templates/list"}`)
+	require.Nil(t, resp.Error)
+	assert.JSONEq(t, `{"resourceTemplates":[{"uriTemplate":"file:///{path}","name":"files"}]}`, string(resp.Result))
+
+	// Legacy names are only accepted when opted in
+	resp = dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":4,"method":"callTool","params":{"name":"echo"}}`)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, jsonrpc.CodeMethodNotFound, resp.Error.Code)
+}
+
+func TestDispatcher_LegacyMethodNames(t *testing.T) {
+	srv, d := newTestDispatcherWithOptions(t, &Options{LegacyMethodNames: true})
+	ctx := initialize(t, d)
+
+	srv.OnListTools(func(ctx context.Context) ([]types.Tool, error) {
+		return []types.Tool{{Name: "echo"}}, nil
+	})
+	srv.OnCallTool(func(ctx context.Context, name string, args map[string]interface{}) (interface{}, error) {
+		return map[string]interface{}{"name": name, "value": args["value"]}, nil
+	})
+	srv.OnReadResource(func(ctx context.Context, uri string) ([]byte, string, error) {
+		return []byte{0xff, 0x00}, "application/octet-stream", nil
+	})
+	srv.OnListPrompts(func(ctx context.Context) ([]types.Prompt, error) {
+		return []types.Prompt{{Name: "greet", Description: "Greets", Arguments: []types.PromptArgument{{Name: "who", Required: true}}}}, nil
+	})
+	srv.OnGetPrompt(func(ctx context.Context, name string, args map[string]interface{}) (*types.GetPromptResult, error) {
+		return &types.GetPromptResult{
+			Description: "Greets",
+			Messages:    []types.PromptMessage{{Role: types.RoleUser, Content: types.NewTextContent("Hello " + args["who"].(string))}},
+		}, nil
+	})
+
+	resp := dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":1,"method":"listTools"}`)
+	require.Nil(t, resp.Error)
+	assert.JSONEq(t, `[{"name":"echo","description":""}]`, string(resp.Result))
+
+	resp = dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":2,"method":"callTool","params":{"name":"echo","args":{"value":"x"}}}`)
+	require.Nil(t, resp.Error)
+	assert.JSONEq(t, `{"name":"echo","value":"x"}`, string(resp.Result))
+
+	resp = dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":3,"method":"readResource","params":{"uri":"file:///a"}}`)
+	require.Nil(t, resp.Error)
+	var content struct {
+		Data     []byte `json:"data"`
+		MimeType string `json:"mimeType"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Result, &content))
+	assert.Equal(t, []byte{0xff, 0x00}, content.Data)
+	assert.Equal(t, "application/octet-stream", content.MimeType)
+
+	resp = dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":5,"method":"listPrompts"}`)
+	require.Nil(t, resp.Error)
+	assert.JSONEq(t, `[{"name":"greet","description":"Greets","parameters":{"who":{"required":true}}}]`, string(resp.Result))
+
+	resp = dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":6,"method":"getPrompt","params":{"name":"greet","args":{"who":"Ada"}}}`)
+	require.Nil(t, resp.Error)
+	assert.JSONEq(t, `{"name":"greet","description":"Greets","messages":[{"role":"user","content":{"type":"text","text":"Hello Ada"}}]}`, string(resp.Result))
+
+	// Spec names keep working alongside the aliases
+	resp = dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":4,"method":"tools/list"}`)
+	require.Nil(t, resp.Error)
+	assert.JSONEq(t, `{"tools":[{"name":"echo","description":""}]}`, string(resp.Result))
+
+	assert.Nil(t, dispatch(t, ctx, d, `{"jsonrpc":"2.0","method":"initialized"}`))
+}
+
+func TestDispatcher_LegacyEnvelope(t *testing.T) {
+	srv, d := newTestDispatcherWithOptions(t, &Options{LegacyMethodNames: true})
+	srv.OnListTools(func(ctx context.Context) ([]types.Tool, error) {
+		return []types.Tool{{Name: "echo"}}, nil
+	})
+
+	handle := func(ctx context.Context, data string) string {
+		out, err := d.Handle(ctx, []byte(data))
+		require.NoError(t, err)
+		return string(out)
+	}
+
+	ctx := WithConnection(context.Background(), NewConnection())
+	out := handle(ctx, `{"method":"initialize","params":{"clientInfo":{"name":"old","version":"0.1"}}}`)
+	var init struct {
+		Result types.InitializeResponse `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &init))
+	assert.Equal(t, types.LatestProtocolVersion, init.Result.ProtocolVersion)
+	assert.NotContains(t, out, `"jsonrpc"`)
+	assert.NotContains(t, out, `"id"`)
+
+	assert.Empty(t, handle(ctx, `{"method":"initialized"}`))
+	assert.JSONEq(t, `{"result":[{"name":"echo","description":""}]}`, handle(ctx, `{"method":"listTools"}`))
+	assert.JSONEq(t, `{"error":{"code":-32601,"message":"method not found: nope"}}`, handle(ctx, `{"method":"nope"}`))
+
+	// Requests are answered in order on asynchronous transports too
+	var replies []string
+	d.HandleAsync(ctx, []byte(`{"method":"listTools"}`), func(out []byte) { replies = append(replies, string(out)) })
+	require.Len(t, replies, 1)
+	assert.JSONEq(t, `{"result":[{"name":"echo","description":""}]}`, replies[0])
+
+	// Without the option the legacy envelope is not a valid request
+	_, d = newTestDispatcher(t)
+	out2, err := d.Handle(WithConnection(context.Background(), NewConnection()), []byte(`{"method":"listTools"}`))
+	require.NoError(t, err)
+	assert.Nil(t, out2)
+}
+
+func TestDispatcher_Errors(t *testing.T) {
+	_, d := newTestDispatcher(t)
+	ctx := initialize(t, d)
+
+	resp := dispatch(t, ctx, d, `{`)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, jsonrpc.CodeParseError, resp.Error.Code)
+	assert.True(t, resp.ID.IsZero())
+
+	resp = dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":"a","method":"nope"}`)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, jsonrpc.CodeMethodNotFound, resp.Error.Code)
+	assert.Equal(t, `"a"`, resp.ID.String())
+
+	resp = dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":4,"method":"tools/call","params":[1,2]}`)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, jsonrpc.CodeInvalidParams, resp.Error.Code)
+
+	// Plain handler errors are reported as internal errors
+	resp = dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":5,"method":"tools/list"}`)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, jsonrpc.CodeInternalError, resp.Error.Code)
+}
+
+func TestDispatcher_NotificationsAreNotAnswered(t *testing.T) {
+	_, d := newTestDispatcher(t)
+	ctx := initialize(t, d)
+
+	assert.Nil(t, dispatch(t, ctx, d, `{"jsonrpc":"2.0","method":"notifications/initialized"}`))
+	assert.Nil(t, dispatch(t, ctx, d, `{"jsonrpc":"2.0","method":"unknown"}`))
+	assert.Nil(t, dispatch(t, ctx, d, `{"jsonrpc":"2.0","method":"notifications/cancelled","params":[1]}`))
+	assert.Nil(t, dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":9,"result":{}}`))
+}
+
+func TestDispatcher_Cancellation(t *testing.T) {
+	srv, d := newTestDispatcher(t)
+	ctx := initialize(t, d)
+
+	started := make(chan struct{})
+	stopped := make(chan error, 1)
+	srv.OnCallTool(func(ctx context.Context, name string, args map[string]interface{}) (interface{}, error) {
+		close(started)
+		<-ctx.Done()
+		stopped <- context.Cause(ctx)
+		return nil, ctx.Err()
+	})
+
+	var replies []string
+	reply := func(data []byte) { replies = append(replies, string(data)) }
+
+	d.HandleAsync(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"slow"}}`), reply)
+	<-started
+	d.HandleAsync(ctx, []byte(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":1,"reason":"user abort"}}`), reply)
+	d.Wait()
+
+	assert.ErrorIs(t, <-stopped, ErrRequestCancelled)
+	assert.Empty(t, replies, "cancelled requests must not be answered")
+
+	// Cancelling a request that already completed is harmless
+	d.HandleAsync(ctx, []byte(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":1}}`), reply)
+	d.HandleAsync(ctx, []byte(`{"jsonrpc":"2.0","id":2,"method":"ping"}`), reply)
+	d.Wait()
+	require.Len(t, replies, 1)
+	assert.Contains(t, replies[0], `"id":2`)
+}
+
+func TestDispatcher_Lifecycle(t *testing.T) {
+	srv, d := newTestDispatcher(t)
+
+	var handlerSession *Session
+	srv.OnListTools(func(ctx context.Context) ([]types.Tool, error) {
+		handlerSession, _ = SessionFromContext(ctx)
+		return nil, nil
+	})
+
+	conn := NewConnection()
+	ctx := WithConnection(context.Background(), conn)
+
+	// Only ping is served before initialize
+	resp := dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, jsonrpc.CodeInvalidRequest, resp.Error.Code)
+
+	resp = dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":2,"method":"ping"}`)
+	require.Nil(t, resp.Error)
+
+	resp = dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":3,"method":"initialize","params":{"protocolVersion":"2025-06-18"}}`)
+	require.Nil(t, resp.Error)
+	session := conn.Session()
+	require.NotNil(t, session)
+	assert.Equal(t, Initializing, session.State())
+
+	resp = dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":4,"method":"tools/list"}`)
+	require.Nil(t, resp.Error)
+	assert.Same(t, session, handlerSession)
+
+	assert.Nil(t, dispatch(t, ctx, d, `{"jsonrpc":"2.0","method":"notifications/initialized"}`))
+	assert.True(t, session.IsInitialized())
+
+	// A connection is bound to a single session
+	resp = dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":5,"method":"initialize","params":{"protocolVersion":"2025-06-18"}}`)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, jsonrpc.CodeInvalidRequest, resp.Error.Code)
+
+	// Other connections get their own session
+	other := NewConnection()
+	resp = dispatch(t, WithConnection(context.Background(), other), d, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18"}}`)
+	require.Nil(t, resp.Error)
+	require.NotNil(t, other.Session())
+	assert.NotEqual(t, session.ID(), other.Session().ID())
+}
+
+func TestToolResult(t *testing.T) {
+	text := types.NewTextContent("hi")
+	tests := []struct {
+		name string
+		in   interface{}
+		want string
+	}{
+		{name: "nil", in: nil, want: `{"content":[]}`},
+		{name: "string", in: "hi", want: `{"content":[{"type":"text","text":"hi"}]}`},
+		{name: "value", in: map[string]int{"n": 1}, want: `{"content":[{"type":"text","text":"{\"n\":1}"}]}`},
+		{name: "content", in: types.NewImageContent([]byte("png"), "image/png"), want: `{"content":[{"type":"image","data":"cG5n","mimeType":"image/png"}]}`},
+		{name: "content list", in: []types.Content{text, text}, want: `{"content":[{"type":"text","text":"hi"},{"type":"text","text":"hi"}]}`},
+		{name: "result", in: types.NewErrorToolResult("failed"), want: `{"content":[{"type":"text","text":"failed"}],"isError":true}`},
+		{name: "result value", in: types.CallToolResult{StructuredContent: map[string]int{"n": 1}}, want: `{"content":[],"structuredContent":{"n":1}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := toolResult(tt.in)
+			require.NoError(t, err)
+			data, err := json.Marshal(result)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(data))
+		})
+	}
+}
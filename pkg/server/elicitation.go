@@ -0,0 +1,150 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonschema"
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+)
+
+// Elicit asks the user of the client for structured input and blocks until
+// they answer. The schema describes the input and must be a flat object
+// schema, see types.ElicitRequest. It is reduced to the keywords the spec
+// allows, such as title, description, format, minimum or enum in properties;
+// others, like the additionalProperties and nullable types jsonschema.For
+// emits for a struct of primitive fields, are dropped before sending.
+// Accepted content is validated against the sent schema. The client must
+// have declared the elicitation capability, which requires protocol version
+// 2025-06-18.
+func (s *Session) Elicit(ctx context.Context, message string, schema json.RawMessage) (*types.ElicitResult, error) {
+	if !s.Supports(types.FeatureElicitation) || !s.CheckClientCapability(types.ClientCapabilities{Elicitation: &types.ElicitationCapability{}}) {
+		return nil, errors.Wrap(ErrUnsupportedByClient, "elicitation")
+	}
+	schema, validator, err := compileElicitationSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	var result types.ElicitResult
+	req := types.ElicitRequest{Message: message, RequestedSchema: schema}
+	if err := s.request(ctx, types.MethodElicitationCreate, req, &result); err != nil {
+		return nil, err
+	}
+
+	switch result.Action {
+	case types.ElicitActionAccept:
+		if result.Content == nil {
+			result.Content = map[string]interface{}{}
+		}
+		res, err := validator.Validate(result.Content)
+		if err != nil {
+			return nil, err
+		}
+		if !res.Valid {
+			return nil, errors.New("invalid elicitation content from client: " + jsonschema.FormatErrors(res))
+		}
+	case types.ElicitActionDecline, types.ElicitActionCancel:
+		result.Content = nil
+	default:
+		return nil, errors.Errorf("invalid elicitation action %q from client", result.Action)
+	}
+	return &result, nil
+}
+
+// elicitationKeywords are the keywords the restricted schemas of
+// elicitation requests allow in properties, by property type
+var elicitationKeywords = map[string][]string{
+	"string":  {"type", "title", "description", "default", "minLength", "maxLength", "format", "enum", "enumNames"},
+	"number":  {"type", "title", "description", "default", "minimum", "maximum"},
+	"integer": {"type", "title", "description", "default", "minimum", "maximum"},
+	"boolean": {"type", "title", "description", "default"},
+}
+
+// elicitationSchema is the restricted object schema of elicitation requests
+type elicitationSchema struct {
+	Type       string                            `json:"type"`
+	Properties map[string]map[string]interface{} `json:"properties"`
+	Required   []string                          `json:"required,omitempty"`
+}
+
+// compileElicitationSchema checks that schema is a flat object schema, whose
+// properties have primitive types, and reduces it to the keywords the spec
+// allows. It returns the reduced schema and its compiled validator.
+func compileElicitationSchema(schema json.RawMessage) (json.RawMessage, *jsonschema.Validator, error) {
+	var parsed elicitationSchema
+	if err := json.Unmarshal(schema, &parsed); err != nil {
+		return nil, nil, errors.Wrap(err, "invalid elicitation schema")
+	}
+	if parsed.Type != "object" {
+		return nil, nil, errors.New("invalid elicitation schema: type must be object")
+	}
+
+	properties := make(map[string]map[string]interface{}, len(parsed.Properties))
+	for name, prop := range parsed.Properties {
+		if prop == nil {
+			return nil, nil, errors.Errorf("invalid elicitation schema: property %s has no schema", name)
+		}
+		typ := primitiveType(prop["type"])
+		keywords, ok := elicitationKeywords[typ]
+		if !ok {
+			return nil, nil, errors.Errorf("invalid elicitation schema: property %s must be a string, number, integer or boolean", name)
+		}
+		reduced := map[string]interface{}{"type": typ}
+		for _, keyword := range keywords[1:] {
+			if value, ok := prop[keyword]; ok {
+				reduced[keyword] = value
+			}
+		}
+		if enum, ok := reduced["enum"].([]interface{}); ok {
+			reduced["enum"] = withoutNull(enum)
+		}
+		properties[name] = reduced
+	}
+
+	reduced, err := json.Marshal(elicitationSchema{Type: "object", Properties: properties, Required: parsed.Required})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "invalid elicitation schema")
+	}
+	validator, err := jsonschema.Compile(reduced)
+	if err != nil {
+		return nil, nil, err
+	}
+	return reduced, validator, nil
+}
+
+// primitiveType returns the type of a property schema, which may be a list
+// of one type and "null", as jsonschema.For emits for optional fields
+func primitiveType(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []interface{}:
+		var typ string
+		for _, t := range v {
+			if t == "null" {
+				continue
+			}
+			if s, ok := t.(string); ok && typ == "" {
+				typ = s
+				continue
+			}
+			return ""
+		}
+		return typ
+	}
+	return ""
+}
+
+// withoutNull returns the values of an enum other than null
+func withoutNull(enum []interface{}) []interface{} {
+	values := make([]interface{}, 0, len(enum))
+	for _, v := range enum {
+		if v != nil {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonschema"
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+)
+
+const nameSchema = `{"type":"object","properties":{"name":{"type":"string","minLength":1},"age":{"type":"integer"}},"required":["name"]}`
+
+func TestSession_Elicit(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   string
+		expected *types.ElicitResult
+		err      string
+	}{
+		{
+			name:     "accept",
+			result:   `{"action":"accept","content":{"name":"Ada","age":36}}`,
+			expected: &types.ElicitResult{Action: types.ElicitActionAccept, Content: map[string]interface{}{"name": "Ada", "age": float64(36)}},
+		},
+		{
+			name:   "accept invalid content",
+			result: `{"action":"accept","content":{"age":"old"}}`,
+			err:    "invalid elicitation content from client",
+		},
+		{
+			name:     "decline",
+			result:   `{"action":"decline","content":{"name":"ignored"}}`,
+			expected: &types.ElicitResult{Action: types.ElicitActionDecline},
+		},
+		{
+			name:     "cancel",
+			result:   `{"action":"cancel"}`,
+			expected: &types.ElicitResult{Action: types.ElicitActionCancel},
+		},
+		{
+			name:   "unknown action",
+			result: `{"action":"maybe"}`,
+			err:    `invalid elicitation action "maybe"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, d := newTestDispatcher(t)
+
+			var got types.ElicitRequest
+			session, _ := initializeClient(t, d, `{"elicitation":{}}`, func(req *jsonrpc.Request) string {
+				require.Equal(t, types.MethodElicitationCreate, req.Method)
+				require.NoError(t, json.Unmarshal(req.Params, &got))
+				return `{"jsonrpc":"2.0","id":` + req.ID.String() + `,"result":` + tt.result + `}`
+			})
+
+			result, err := session.Elicit(context.Background(), "Who are you?", json.RawMessage(nameSchema))
+			if tt.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+			assert.Equal(t, "Who are you?", got.Message)
+			assert.JSONEq(t, nameSchema, string(got.RequestedSchema))
+		})
+	}
+}
+
+func TestSession_ElicitReducesSchema(t *testing.T) {
+	type contact struct {
+		Name  string  `json:"name" jsonschema:"description=Full name"`
+		Email *string `json:"email,omitempty" jsonschema:"format=email"`
+	}
+	schema, err := jsonschema.For[contact]()
+	require.NoError(t, err)
+	data, err := json.Marshal(schema)
+	require.NoError(t, err)
+
+	_, d := newTestDispatcher(t)
+	var got types.ElicitRequest
+	session, _ := initializeClient(t, d, `{"elicitation":{}}`, func(req *jsonrpc.Request) string {
+		require.NoError(t, json.Unmarshal(req.Params, &got))
+		return `{"jsonrpc":"2.0","id":` + req.ID.String() + `,"result":{"action":"accept","content":{"name":"Ada"}}}`
+	})
+
+	result, err := session.Elicit(context.Background(), "Who are you?", data)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "Ada"}, result.Content)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "description": "Full name"},
+			"email": {"type": "string", "format": "email"}
+		},
+		"required": ["name"]
+	}`, string(got.RequestedSchema))
+}
+
+func TestSession_ElicitErrors(t *testing.T) {
+	t.Run("capability not declared", func(t *testing.T) {
+		_, d := newTestDispatcher(t)
+		session, _ := initializeClient(t, d, `{}`, nil)
+
+		_, err := session.Elicit(context.Background(), "Who are you?", json.RawMessage(nameSchema))
+		assert.Equal(t, ErrUnsupportedByClient, errors.Cause(err))
+	})
+
+	t.Run("nested schema", func(t *testing.T) {
+		_, d := newTestDispatcher(t)
+		session, _ := initializeClient(t, d, `{"elicitation":{}}`, nil)
+
+		schema := `{"type":"object","properties":{"address":{"type":"object","properties":{"city":{"type":"string"}}}}}`
+		_, err := session.Elicit(context.Background(), "Where do you live?", json.RawMessage(schema))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "property address must be a string, number, integer or boolean")
+	})
+}
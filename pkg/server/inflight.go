@@ -0,0 +1,104 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
+)
+
+// ErrRequestCancelled is the cancellation cause of a request the client
+// cancelled with notifications/cancelled. Handlers can tell it apart from
+// other cancellations with context.Cause.
+var ErrRequestCancelled = errors.New("request cancelled by client")
+
+// ErrSessionClosed is the cancellation cause of requests whose session was
+// closed while they were running
+var ErrSessionClosed = errors.New("session closed")
+
+// inflightRequest is a request currently being handled
+type inflightRequest struct {
+	cancel context.CancelCauseFunc
+}
+
+// inflightRequests tracks the requests being handled so they can be
+// cancelled by ID. IDs are only unique within a session, so requests are
+// keyed by both.
+type inflightRequests struct {
+	mu       sync.Mutex
+	requests map[string]*inflightRequest
+}
+
+// track registers a request and returns the context its handler must run
+// with. The returned function must be called once the request completes.
+func (r *inflightRequests) track(ctx context.Context, id jsonrpc.ID) (context.Context, func()) {
+	key := inflightKey(ctx, id)
+	ctx, cancel := context.WithCancelCause(ctx)
+	req := &inflightRequest{cancel: cancel}
+
+	r.mu.Lock()
+	if r.requests == nil {
+		r.requests = make(map[string]*inflightRequest)
+	}
+	r.requests[key] = req
+	r.mu.Unlock()
+
+	return ctx, func() {
+		r.mu.Lock()
+		// A newer request may have reused the ID
+		if r.requests[key] == req {
+			delete(r.requests, key)
+		}
+		r.mu.Unlock()
+		cancel(nil)
+	}
+}
+
+// cancel cancels the request with the given ID in the session of ctx. It
+// reports whether the request was still in flight.
+func (r *inflightRequests) cancel(ctx context.Context, id jsonrpc.ID) bool {
+	r.mu.Lock()
+	req, ok := r.requests[inflightKey(ctx, id)]
+	r.mu.Unlock()
+
+	if ok {
+		req.cancel(ErrRequestCancelled)
+	}
+	return ok
+}
+
+// cancelSession cancels every request of the session
+func (r *inflightRequests) cancelSession(sessionID string) {
+	prefix := sessionID + "/"
+
+	r.mu.Lock()
+	var cancels []context.CancelCauseFunc
+	for key, req := range r.requests {
+		if strings.HasPrefix(key, prefix) {
+			cancels = append(cancels, req.cancel)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, cancel := range cancels {
+		cancel(ErrSessionClosed)
+	}
+}
+
+// inflightKey returns the key of a request within the session of ctx
+func inflightKey(ctx context.Context, id jsonrpc.ID) string {
+	sessionID := ""
+	if session, ok := SessionFromContext(ctx); ok {
+		sessionID = session.ID()
+	}
+	return sessionID + "/" + id.String()
+}
+
+// isCancelled reports whether the request running with ctx was cancelled by
+// the client
+func isCancelled(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), ErrRequestCancelled)
+}
@@ -0,0 +1,286 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+)
+
+// legacyMethod maps a method name used before the SDK adopted the spec names
+// onto its spec equivalent
+type legacyMethod struct {
+	// method is the spec method the legacy name is an alias for
+	method string
+	// params optionally rewrites legacy params into the spec shape
+	params func(json.RawMessage) (json.RawMessage, error)
+	// result optionally rewrites the spec result of the spec request msg
+	// into the legacy shape
+	result func(msg *jsonrpc.Message, v interface{}) (interface{}, error)
+}
+
+// legacyMethods is the alias table consulted when Options.LegacyMethodNames
+// is set
+var legacyMethods = map[string]legacyMethod{
+	"listTools": {
+		method: types.MethodToolsList,
+		result: func(_ *jsonrpc.Message, v interface{}) (interface{}, error) {
+			return v.(*types.ListToolsResult).Tools, nil
+		},
+	},
+	"callTool": {
+		method: types.MethodToolsCall,
+		params: renameParam("args", "arguments"),
+		result: legacyToolResult,
+	},
+	"listPrompts": {
+		method: types.MethodPromptsList,
+		result: legacyPromptsResult,
+	},
+	"getPrompt": {
+		method: types.MethodPromptsGet,
+		params: renameParam("args", "arguments"),
+		result: legacyPromptResult,
+	},
+	"listResources": {
+		method: types.MethodResourcesList,
+		result: func(_ *jsonrpc.Message, v interface{}) (interface{}, error) {
+			return v.(*types.ListResourcesResult).Resources, nil
+		},
+	},
+	"readResource": {
+		method: types.MethodResourcesRead,
+		result: legacyResourceResult,
+	},
+	"listResourceTemplates": {
+		method: types.MethodResourcesTemplatesList,
+		result: func(_ *jsonrpc.Message, v interface{}) (interface{}, error) {
+			return v.(*types.ListResourceTemplatesResult).ResourceTemplates, nil
+		},
+	},
+	"initialized": {
+		method: types.NotificationInitialized,
+	},
+	"cancel": {
+		method: types.NotificationCancelled,
+		params: renameParam("id", "requestId"),
+	},
+}
+
+// registerLegacyMethods registers every legacy alias on top of the spec
+// handlers it maps to
+func (d *Dispatcher) registerLegacyMethods() {
+	for name, alias := range legacyMethods {
+		alias := alias
+		if handler, ok := d.requests[alias.method]; ok {
+			d.requests[name] = func(ctx context.Context, msg *jsonrpc.Message) (interface{}, error) {
+				specMsg, err := alias.rewrite(msg)
+				if err != nil {
+					return nil, err
+				}
+				result, err := handler(ctx, specMsg)
+				if err != nil || alias.result == nil {
+					return result, err
+				}
+				return alias.result(specMsg, result)
+			}
+		}
+		if handler, ok := d.notifications[alias.method]; ok {
+			d.notifications[name] = func(ctx context.Context, msg *jsonrpc.Message) error {
+				specMsg, err := alias.rewrite(msg)
+				if err != nil {
+					return err
+				}
+				return handler(ctx, specMsg)
+			}
+		}
+	}
+}
+
+// legacyEnvelope is the framing of legacy clients: a method and params, with
+// neither a jsonrpc member nor an ID
+type legacyEnvelope struct {
+	JSONRPC json.RawMessage `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+// legacyResponse answers a request sent in the legacy envelope
+type legacyResponse struct {
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *types.Error    `json:"error,omitempty"`
+}
+
+// decodeLegacy decodes a message sent in the legacy envelope. It reports
+// false for anything else, or when legacy method names are not enabled.
+func (d *Dispatcher) decodeLegacy(data []byte) (*jsonrpc.Message, bool) {
+	if !d.server.legacyMethodNames {
+		return nil, false
+	}
+	var env legacyEnvelope
+	if err := json.Unmarshal(data, &env); err != nil || env.JSONRPC != nil || env.ID != nil || env.Method == "" {
+		return nil, false
+	}
+	return &jsonrpc.Message{JSONRPC: jsonrpc.Version, Method: env.Method, Params: env.Params}, true
+}
+
+// handleLegacy handles a message sent in the legacy envelope. Methods known
+// as notifications are not answered. Anything else is a request, given an ID
+// of its own so it can be tracked, and answered with a legacyResponse.
+func (d *Dispatcher) handleLegacy(ctx context.Context, msg *jsonrpc.Message) ([]byte, error) {
+	if _, ok := d.notifications[msg.Method]; ok {
+		d.handleNotification(ctx, msg)
+		return nil, nil
+	}
+
+	id := jsonrpc.NewStringID("legacy-" + strconv.FormatInt(atomic.AddInt64(&d.legacyIDs, 1), 10))
+	msg.ID = &id
+	resp := d.HandleMessage(ctx, msg)
+	if resp == nil {
+		return nil, nil
+	}
+	if resp.Error != nil {
+		return json.Marshal(legacyResponse{Error: &types.Error{Code: resp.Error.Code, Message: resp.Error.Message}})
+	}
+	result := resp.Result
+	if len(result) == 0 {
+		result = json.RawMessage("null")
+	}
+	return json.Marshal(legacyResponse{Result: result})
+}
+
+// rewrite returns a copy of msg addressed to the spec method
+func (a legacyMethod) rewrite(msg *jsonrpc.Message) (*jsonrpc.Message, error) {
+	specMsg := *msg
+	specMsg.Method = a.method
+	if a.params != nil && len(msg.Params) > 0 {
+		params, err := a.params(msg.Params)
+		if err != nil {
+			return nil, jsonrpc.NewError(jsonrpc.CodeInvalidParams, "invalid params: "+err.Error())
+		}
+		specMsg.Params = params
+	}
+	return &specMsg, nil
+}
+
+// renameParam returns a params rewriter that renames a top-level member
+func renameParam(from, to string) func(json.RawMessage) (json.RawMessage, error) {
+	return func(raw json.RawMessage) (json.RawMessage, error) {
+		var params map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &params); err != nil {
+			return nil, err
+		}
+		if value, ok := params[from]; ok {
+			params[to] = value
+			delete(params, from)
+		}
+		return json.Marshal(params)
+	}
+}
+
+// legacyToolResult unwraps a tools/call result into the bare value legacy
+// clients expect. Structured content is returned as is and JSON text is
+// decoded back into its value.
+func legacyToolResult(_ *jsonrpc.Message, v interface{}) (interface{}, error) {
+	result := v.(*types.CallToolResult)
+	if result.StructuredContent != nil {
+		return result.StructuredContent, nil
+	}
+	if len(result.Content) != 1 || result.Content[0].ContentType() != types.ContentTypeText {
+		return result.Content, nil
+	}
+
+	text := result.Text()
+	var value interface{}
+	if err := json.Unmarshal([]byte(text), &value); err == nil {
+		return value, nil
+	}
+	return text, nil
+}
+
+// legacyResourceResult converts a resources/read result into the single
+// data/mimeType object legacy clients expect
+func legacyResourceResult(_ *jsonrpc.Message, v interface{}) (interface{}, error) {
+	result := v.(*types.ReadResourceResult)
+	if len(result.Contents) == 0 {
+		return nil, errors.New("resource has no contents")
+	}
+
+	contents := result.Contents[0]
+	data, err := contents.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	return struct {
+		Data     []byte `json:"data"`
+		MimeType string `json:"mimeType"`
+	}{
+		Data:     data,
+		MimeType: contents.MimeType,
+	}, nil
+}
+
+// legacyPrompt is the prompt shape of legacy clients, whose parameters map
+// argument names to their description
+type legacyPrompt struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Parameters  map[string]interface{} `json:"parameters,omitempty"`
+	// Messages holds the rendered messages of getPrompt results
+	Messages []types.PromptMessage `json:"messages,omitempty"`
+}
+
+// legacyParameters converts prompt arguments into legacy parameters
+func legacyParameters(args []types.PromptArgument) map[string]interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	params := make(map[string]interface{}, len(args))
+	for _, arg := range args {
+		param := map[string]interface{}{"required": arg.Required}
+		if arg.Description != "" {
+			param["description"] = arg.Description
+		}
+		if len(arg.Schema) > 0 {
+			param["schema"] = arg.Schema
+		}
+		params[arg.Name] = param
+	}
+	return params
+}
+
+// legacyPromptsResult converts a prompts/list result into the list of
+// legacy prompts
+func legacyPromptsResult(_ *jsonrpc.Message, v interface{}) (interface{}, error) {
+	prompts := v.(*types.ListPromptsResult).Prompts
+	result := make([]legacyPrompt, 0, len(prompts))
+	for _, prompt := range prompts {
+		result = append(result, legacyPrompt{
+			Name:        prompt.Name,
+			Description: prompt.Description,
+			Parameters:  legacyParameters(prompt.Arguments),
+		})
+	}
+	return result, nil
+}
+
+// legacyPromptResult converts a prompts/get result into the legacy prompt
+// legacy clients expect, named after the requested prompt
+func legacyPromptResult(msg *jsonrpc.Message, v interface{}) (interface{}, error) {
+	var req types.GetPromptRequest
+	if err := msg.UnmarshalParams(&req); err != nil {
+		return nil, err
+	}
+	result := v.(*types.GetPromptResult)
+	return legacyPrompt{
+		Name:        req.Name,
+		Description: result.Description,
+		Messages:    result.Messages,
+	}, nil
+}
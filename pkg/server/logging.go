@@ -0,0 +1,136 @@
+package server
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+)
+
+// SetLogLevel sets the minimum level of the log messages sent to the client
+// of the session
+func (s *Session) SetLogLevel(level types.LoggingLevel) error {
+	if !level.Valid() {
+		return jsonrpc.NewError(jsonrpc.CodeInvalidParams, "invalid logging level: "+string(level))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logLevel = level
+	return nil
+}
+
+// LogLevel returns the minimum level of the log messages sent to the client,
+// empty until the client sets one
+func (s *Session) LogLevel() types.LoggingLevel {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.logLevel
+}
+
+// Log sends a log message to the client as notifications/message when its
+// level is at least the minimum level the client set. Nothing is sent
+// before the client sets a level. Logger optionally names the source of the
+// message and data is any JSON value.
+func (s *Session) Log(ctx context.Context, level types.LoggingLevel, logger string, data interface{}) error {
+	min := s.LogLevel()
+	if min == "" || !level.AtLeast(min) {
+		return nil
+	}
+	return notify(ctx, s, types.NotificationMessage, types.LoggingMessageNotification{
+		Level:  level,
+		Logger: logger,
+		Data:   data,
+	})
+}
+
+// Log sends a log message to the client of the session handled with ctx,
+// see Session.Log. It does nothing outside of a session.
+func Log(ctx context.Context, level types.LoggingLevel, logger string, data interface{}) error {
+	session, ok := SessionFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	return session.Log(ctx, level, logger, data)
+}
+
+// SetLogLevel handles logging/setLevel requests for the session of ctx
+func (s *Server) SetLogLevel(ctx context.Context, req *types.SetLevelRequest) error {
+	session, ok := SessionFromContext(ctx)
+	if !ok {
+		return errors.New("no session to set the logging level of")
+	}
+	return session.SetLogLevel(req.Level)
+}
+
+// NewSessionLogger returns a logger that writes to local and also forwards
+// the messages logged with the context of a session to its client, as
+// notifications/message named after the bucket. Used as Options.Logger,
+// clients see the logs of their requests at the level they set.
+func NewSessionLogger(local types.Logger) types.Logger {
+	return &sessionLogger{local: local}
+}
+
+// sessionLogger tees logs to a local logger and to the client of the
+// session found in the context
+type sessionLogger struct {
+	local types.Logger
+}
+
+// forwardingKey marks the context of forwarded messages, so what transports
+// log while delivering them is not forwarded in turn
+type forwardingKey struct{}
+
+func (l *sessionLogger) Access(ctx context.Context, message string) {
+	l.local.Access(ctx, message)
+}
+
+func (l *sessionLogger) Info(ctx context.Context, bucket, handler, message string) {
+	l.local.Info(ctx, bucket, handler, message)
+	l.forward(ctx, types.LoggingLevelInfo, bucket, handler, message)
+}
+
+func (l *sessionLogger) Warn(ctx context.Context, bucket, handler, message string) {
+	l.local.Warn(ctx, bucket, handler, message)
+	l.forward(ctx, types.LoggingLevelWarning, bucket, handler, message)
+}
+
+func (l *sessionLogger) Error(ctx context.Context, bucket, handler, message string) {
+	l.local.Error(ctx, bucket, handler, message)
+	l.forward(ctx, types.LoggingLevelError, bucket, handler, message)
+}
+
+func (l *sessionLogger) Panic(ctx context.Context, bucket, handler, message string) {
+	// The local logger panics, so the client is told first
+	l.forward(ctx, types.LoggingLevelCritical, bucket, handler, message)
+	l.local.Panic(ctx, bucket, handler, message)
+}
+
+func (l *sessionLogger) V(n int) bool {
+	return l.local.V(n)
+}
+
+func (l *sessionLogger) Sub(name string) types.Logger {
+	return &sessionLogger{local: l.local.Sub(name)}
+}
+
+func (l *sessionLogger) SubWithIncrement(name string, n int) types.Logger {
+	return &sessionLogger{local: l.local.SubWithIncrement(name, n)}
+}
+
+// forward sends a message to the client of the session of ctx, if any
+func (l *sessionLogger) forward(ctx context.Context, level types.LoggingLevel, bucket, handler, message string) {
+	if ctx == nil || ctx.Value(forwardingKey{}) != nil {
+		return
+	}
+	session, ok := SessionFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	ctx = context.WithValue(ctx, forwardingKey{}, true)
+	// Failures are not logged, which could loop
+	_ = session.Log(ctx, level, bucket, map[string]string{"handler": handler, "message": message})
+}
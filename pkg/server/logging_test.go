@@ -0,0 +1,70 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+)
+
+// logMessages decodes the log messages among the recorded notifications
+func logMessages(t *testing.T, notifier *answeringNotifier) []types.LoggingMessageNotification {
+	notifier.mu.Lock()
+	defer notifier.mu.Unlock()
+
+	var messages []types.LoggingMessageNotification
+	for _, notification := range notifier.notifications {
+		require.Equal(t, types.NotificationMessage, notification.Method)
+		var msg types.LoggingMessageNotification
+		require.NoError(t, json.Unmarshal(notification.Params, &msg))
+		messages = append(messages, msg)
+	}
+	return messages
+}
+
+func TestSession_Log(t *testing.T) {
+	_, d := newTestDispatcher(t)
+	session, notifier := initializeClient(t, d, `{}`, nil)
+
+	// Nothing is sent before the client sets a level
+	require.NoError(t, session.Log(context.Background(), types.LoggingLevelEmergency, "db", "down"))
+	assert.Empty(t, logMessages(t, notifier))
+
+	resp := dispatch(t, notifier.ctx, d, `{"jsonrpc":"2.0","id":1,"method":"logging/setLevel","params":{"level":"loud"}}`)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, jsonrpc.CodeInvalidParams, resp.Error.Code)
+
+	resp = dispatch(t, notifier.ctx, d, `{"jsonrpc":"2.0","id":2,"method":"logging/setLevel","params":{"level":"warning"}}`)
+	require.Nil(t, resp.Error)
+	assert.Equal(t, types.LoggingLevelWarning, session.LogLevel())
+
+	require.NoError(t, session.Log(context.Background(), types.LoggingLevelInfo, "db", "connected"))
+	require.NoError(t, session.Log(context.Background(), types.LoggingLevelWarning, "db", "slow"))
+	require.NoError(t, session.Log(context.Background(), types.LoggingLevelError, "", map[string]interface{}{"query": "select"}))
+	assert.Equal(t, []types.LoggingMessageNotification{
+		{Level: types.LoggingLevelWarning, Logger: "db", Data: "slow"},
+		{Level: types.LoggingLevelError, Data: map[string]interface{}{"query": "select"}},
+	}, logMessages(t, notifier))
+}
+
+func TestSessionLogger(t *testing.T) {
+	_, d := newTestDispatcher(t)
+	session, notifier := initializeClient(t, d, `{}`, nil)
+	require.NoError(t, session.SetLogLevel(types.LoggingLevelInfo))
+
+	logger := NewSessionLogger(types.NewNoOpLogger()).Sub("tools")
+	logger.Info(notifier.ctx, "server", "call", "calling")
+	logger.Warn(notifier.ctx, "server", "call", "retrying")
+	// Logs outside of a session stay local
+	logger.Error(context.Background(), "server", "call", "failed")
+
+	assert.Equal(t, []types.LoggingMessageNotification{
+		{Level: types.LoggingLevelInfo, Logger: "server", Data: map[string]interface{}{"handler": "call", "message": "calling"}},
+		{Level: types.LoggingLevelWarning, Logger: "server", Data: map[string]interface{}{"handler": "call", "message": "retrying"}},
+	}, logMessages(t, notifier))
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
+)
+
+// ErrNoNotifier is returned when notifying a session whose transport cannot
+// deliver notifications
+var ErrNoNotifier = errors.New("session cannot receive notifications")
+
+// Notifier delivers notifications to the client of a session. Transports
+// implement it for the connections they serve.
+type Notifier interface {
+	Notify(ctx context.Context, notification *jsonrpc.Notification) error
+}
+
+// notifierKey is the context key of the notifier of a request
+type notifierKey struct{}
+
+// WithNotifier returns a context whose request notifications, such as
+// progress, are delivered by notifier rather than by the notifier of the
+// session. Transports use it to send the notifications of a request along
+// with its response.
+func WithNotifier(ctx context.Context, notifier Notifier) context.Context {
+	return context.WithValue(ctx, notifierKey{}, notifier)
+}
+
+// notify sends a notification about the request handled with ctx, through
+// the notifier of the request if there is one and it belongs to session
+func notify(ctx context.Context, session *Session, method string, params interface{}) error {
+	notifier, ok := ctx.Value(notifierKey{}).(Notifier)
+	if requestSession, found := SessionFromContext(ctx); !ok || (found && requestSession != session) {
+		return session.Notify(ctx, method, params)
+	}
+
+	notification, err := jsonrpc.NewNotification(method, params)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode notification")
+	}
+	return notifier.Notify(ctx, notification)
+}
+
+// broadcast sends a notification to every initialized session that can
+// receive notifications. Failures are logged, not returned, since one broken
+// connection must not keep the others from being notified.
+func (s *Server) broadcast(ctx context.Context, method string, params interface{}) {
+	for _, session := range s.sessions.List() {
+		if !session.IsInitialized() {
+			continue
+		}
+		if err := session.Notify(ctx, method, params); err != nil && !errors.Is(err, ErrNoNotifier) {
+			s.logger.Warn(ctx, "server", "notify", "Failed to send "+method+" to session "+session.ID()+": "+err.Error())
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
+)
+
+// PageFunc returns the page of a list starting at cursor, which is empty for
+// the first page, along with the cursor of the next page, or an empty
+// cursor on the last page. Handler authors can use Server.EncodeCursor to
+// build cursors clients cannot forge.
+type PageFunc[T any] func(ctx context.Context, cursor string) (items []T, nextCursor string, err error)
+
+// ErrInvalidCursor is returned for cursors that were not issued by the
+// server or have been tampered with
+var ErrInvalidCursor = jsonrpc.NewError(jsonrpc.CodeInvalidParams, "invalid cursor")
+
+// newCursorSecret returns a random key for signing cursors
+func newCursorSecret() []byte {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		panic("failed to generate cursor secret: " + err.Error())
+	}
+	return secret
+}
+
+// EncodeCursor encodes v into an opaque cursor. The cursor is signed, so
+// DecodeCursor detects cursors that were modified by clients.
+func (s *Server) EncodeCursor(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encode cursor")
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(s.signCursor(payload)), nil
+}
+
+// DecodeCursor decodes a cursor made by EncodeCursor into v. It returns
+// ErrInvalidCursor if the cursor was not issued by the server.
+func (s *Server) DecodeCursor(cursor string, v interface{}) error {
+	payload, sig, ok := strings.Cut(cursor, ".")
+	if !ok {
+		return ErrInvalidCursor
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(mac, s.signCursor(payload)) {
+		return ErrInvalidCursor
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return ErrInvalidCursor
+	}
+	return nil
+}
+
+// signCursor returns the signature of a cursor payload
+func (s *Server) signCursor(payload string) []byte {
+	mac := hmac.New(sha256.New, s.cursorSecret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+// listCursor is the state of a list served by paginate
+type listCursor struct {
+	// List is the list the cursor belongs to
+	List string `json:"l"`
+	// Offset is the position in the items of the registry and of the
+	// handler returning whole lists
+	Offset int `json:"o,omitempty"`
+	// Paged is set once the registry items have been served and the page
+	// handler has taken over, with its own cursor in Inner
+	Paged bool   `json:"p,omitempty"`
+	Inner string `json:"i,omitempty"`
+}
+
+// listSources are the sources a list is made of. The registry items come
+// first, followed by the items of the page handler if there is one, or else
+// those of the handler returning the whole list.
+type listSources[T any] struct {
+	name     string
+	registry func() []T
+	all      HandlerFunc[[]T]
+	page     PageFunc[T]
+}
+
+// paginate returns the page of a list starting at cursor, and the cursor of
+// the next page. Registry and whole list items are split in pages of
+// Options.PageSize items.
+func paginate[T any](ctx context.Context, s *Server, src listSources[T], cursor string) ([]T, string, error) {
+	var registry []T
+	if src.registry != nil {
+		registry = src.registry()
+	}
+	if len(registry) == 0 && src.all == nil && src.page == nil {
+		return nil, "", errors.New("list " + src.name + " handler not registered")
+	}
+
+	state := listCursor{List: src.name}
+	if cursor != "" {
+		if err := s.DecodeCursor(cursor, &state); err != nil {
+			return nil, "", err
+		}
+		if state.List != src.name {
+			return nil, "", ErrInvalidCursor
+		}
+	}
+	if state.Paged {
+		return paginateHandler(ctx, s, src, state.Inner)
+	}
+
+	items := registry
+	if src.page == nil && src.all != nil {
+		all, err := src.all(ctx)
+		if err != nil {
+			return nil, "", err
+		}
+		items = append(items[:len(items):len(items)], all...)
+	}
+	if state.Offset > len(items) {
+		return nil, "", ErrInvalidCursor
+	}
+	if state.Offset == len(items) && src.page != nil {
+		return paginateHandler(ctx, s, src, "")
+	}
+
+	end := len(items)
+	if s.pageSize > 0 && state.Offset+s.pageSize < end {
+		end = state.Offset + s.pageSize
+	}
+	page := items[state.Offset:end]
+
+	var next *listCursor
+	switch {
+	case end < len(items):
+		next = &listCursor{List: src.name, Offset: end}
+	case src.page != nil:
+		next = &listCursor{List: src.name, Paged: true}
+	}
+	if next == nil {
+		return page, "", nil
+	}
+	nextCursor, err := s.EncodeCursor(next)
+	if err != nil {
+		return nil, "", err
+	}
+	return page, nextCursor, nil
+}
+
+// paginateHandler returns a page of the page handler, wrapping its cursor
+func paginateHandler[T any](ctx context.Context, s *Server, src listSources[T], cursor string) ([]T, string, error) {
+	items, next, err := src.page(ctx, cursor)
+	if err != nil || next == "" {
+		return items, "", err
+	}
+	nextCursor, err := s.EncodeCursor(listCursor{List: src.name, Paged: true, Inner: next})
+	if err != nil {
+		return nil, "", err
+	}
+	return items, nextCursor, nil
+}
+
+// collect walks every page of a list
+func collect[T any](ctx context.Context, s *Server, src listSources[T]) ([]T, error) {
+	var all []T
+	cursor := ""
+	for {
+		items, next, err := paginate(ctx, s, src, cursor)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+		if next == "" {
+			return all, nil
+		}
+		cursor = next
+	}
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+)
+
+func TestServer_Cursor(t *testing.T) {
+	srv, _ := newTestDispatcher(t)
+
+	cursor, err := srv.EncodeCursor(map[string]int{"offset": 10})
+	require.NoError(t, err)
+
+	var got map[string]int
+	require.NoError(t, srv.DecodeCursor(cursor, &got))
+	assert.Equal(t, map[string]int{"offset": 10}, got)
+
+	other, _ := newTestDispatcher(t)
+	assert.Equal(t, ErrInvalidCursor, other.DecodeCursor(cursor, &got), "cursor of another server")
+	assert.Equal(t, ErrInvalidCursor, srv.DecodeCursor("x"+cursor, &got), "tampered cursor")
+	assert.Equal(t, ErrInvalidCursor, srv.DecodeCursor("garbage", &got))
+}
+
+func TestServer_ListPages(t *testing.T) {
+	srv, d := newTestDispatcherWithOptions(t, &Options{PageSize: 2})
+	ctx := initialize(t, d)
+
+	echo := func(ctx context.Context, args map[string]interface{}) (interface{}, error) { return nil, nil }
+	for i := 0; i < 3; i++ {
+		require.NoError(t, srv.AddTool(types.Tool{Name: fmt.Sprintf("registered%d", i)}, echo))
+	}
+	// Page handler cursors are wrapped by the server
+	srv.OnListToolsPage(func(ctx context.Context, cursor string) ([]types.Tool, string, error) {
+		if cursor == "" {
+			return []types.Tool{{Name: "paged0"}}, "more", nil
+		}
+		assert.Equal(t, "more", cursor)
+		return []types.Tool{{Name: "paged1"}}, "", nil
+	})
+
+	var (
+		names  []string
+		cursor string
+		pages  int
+	)
+	for {
+		tools, next, err := srv.ListToolsPage(ctx, cursor)
+		require.NoError(t, err)
+		assert.NotEqual(t, "more", next)
+		for _, tool := range tools {
+			names = append(names, tool.Name)
+		}
+		pages++
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	assert.Equal(t, []string{"registered0", "registered1", "registered2", "paged0", "paged1"}, names)
+	assert.Equal(t, 4, pages)
+
+	all, err := srv.ListTools(ctx)
+	require.NoError(t, err)
+	assert.Len(t, all, 5)
+
+	resp := dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+	require.Nil(t, resp.Error)
+	var result types.ListToolsResult
+	require.NoError(t, json.Unmarshal(resp.Result, &result))
+	assert.Len(t, result.Tools, 2)
+	require.NotEmpty(t, result.NextCursor)
+
+	// Cursors are bound to their list
+	srv.OnListPrompts(func(ctx context.Context) ([]types.Prompt, error) { return nil, nil })
+	_, _, err = srv.ListPromptsPage(ctx, result.NextCursor)
+	assert.Equal(t, ErrInvalidCursor, err)
+
+	resp = dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":2,"method":"tools/list","params":{"cursor":"forged"}}`)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, jsonrpc.CodeInvalidParams, resp.Error.Code)
+}
+
+func TestServer_ListWithoutPageSize(t *testing.T) {
+	srv, d := newTestDispatcher(t)
+	ctx := initialize(t, d)
+
+	srv.OnListResources(func(ctx context.Context) ([]types.Resource, error) {
+		return []types.Resource{{URI: "file:///a"}, {URI: "file:///b"}}, nil
+	})
+
+	resources, next, err := srv.ListResourcesPage(ctx, "")
+	require.NoError(t, err)
+	assert.Len(t, resources, 2)
+	assert.Empty(t, next)
+
+	_, _, err = srv.ListResourceTemplatesPage(ctx, "")
+	assert.EqualError(t, err, "list resource templates handler not registered")
+}
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/keepalive"
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+)
+
+// Ping checks that the client of the session is alive and returns the
+// round-trip time
+func (s *Session) Ping(ctx context.Context) (time.Duration, error) {
+	start := time.Now()
+	if err := s.request(ctx, types.MethodPing, nil, nil); err != nil {
+		return 0, err
+	}
+	return time.Since(start), nil
+}
+
+// keepSessionAlive pings the client of the session until the session ends,
+// closing it once the client stops answering
+func (s *Server) keepSessionAlive(session *Session) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-session.Done()
+		cancel()
+	}()
+
+	s.keepAlive.Run(ctx, func(ctx context.Context) error {
+		_, err := session.Ping(ctx)
+		if errors.Is(err, ErrNoNotifier) {
+			// The client cannot be reached right now, e.g. an HTTP client
+			// without an event stream, which does not mean it is gone
+			return keepalive.ErrSkipped
+		}
+		return err
+	}, func(err error) {
+		s.logger.Warn(context.Background(), "server", "keepalive", "Closing unresponsive session "+session.ID()+": "+err.Error())
+		_ = s.CloseSession(session.ID())
+	})
+}
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
+	"github.com/harriteja/mcp-go-sdk/pkg/keepalive"
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+)
+
+// answerPing answers pings with an empty result
+func answerPing(req *jsonrpc.Request) string {
+	return `{"jsonrpc":"2.0","id":` + req.ID.String() + `,"result":{}}`
+}
+
+func TestSession_Ping(t *testing.T) {
+	_, d := newTestDispatcher(t)
+	session, _ := initializeClient(t, d, `{}`, func(req *jsonrpc.Request) string {
+		require.Equal(t, types.MethodPing, req.Method)
+		return answerPing(req)
+	})
+
+	rtt, err := session.Ping(context.Background())
+	require.NoError(t, err)
+	assert.Greater(t, rtt, time.Duration(0))
+
+	// Pings from the client are answered with an empty result
+	ctx := initialize(t, d)
+	resp := dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":1,"method":"ping"}`)
+	require.Nil(t, resp.Error)
+	assert.JSONEq(t, `{}`, string(resp.Result))
+}
+
+func TestServer_KeepAlive(t *testing.T) {
+	srv, d := newTestDispatcherWithOptions(t, &Options{
+		KeepAlive: keepalive.Options{Interval: 5 * time.Millisecond, MaxMissed: 2},
+	})
+
+	alive, _ := initializeClient(t, d, `{}`, answerPing)
+	dead, _ := initializeClient(t, d, `{}`, nil)
+	// Sessions that cannot receive requests are not pinged
+	unreachable, ok := SessionFromContext(initialize(t, d))
+	require.True(t, ok)
+	t.Cleanup(func() {
+		_ = srv.CloseSession(alive.ID())
+		_ = srv.CloseSession(unreachable.ID())
+	})
+
+	select {
+	case <-dead.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("unresponsive session was not closed")
+	}
+	_, found := srv.Session(dead.ID())
+	assert.False(t, found)
+
+	// Give the other sessions the time to miss pings too
+	time.Sleep(50 * time.Millisecond)
+	for _, session := range []*Session{alive, unreachable} {
+		_, found := srv.Session(session.ID())
+		assert.True(t, found)
+	}
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+)
+
+// progressKey is the context key of the progress reporter of a request
+type progressKey struct{}
+
+// progressReporter sends the progress of a request that carried a progress
+// token to the client that sent it
+type progressReporter struct {
+	// ctx is the context of the request, done once the response is sent
+	ctx     context.Context
+	session *Session
+	token   jsonrpc.ID
+
+	mu   sync.Mutex
+	last *float64
+}
+
+// withProgress returns a context reporting progress for msg when it asked
+// for progress with a token in _meta
+func withProgress(ctx context.Context, msg *jsonrpc.Message) context.Context {
+	var params struct {
+		Meta *types.RequestMeta `json:"_meta"`
+	}
+	// Params that are not objects carry no _meta
+	if len(msg.Params) == 0 || json.Unmarshal(msg.Params, &params) != nil {
+		return ctx
+	}
+	if params.Meta == nil || params.Meta.ProgressToken == nil {
+		return ctx
+	}
+	session, ok := SessionFromContext(ctx)
+	if !ok {
+		return ctx
+	}
+
+	reporter := &progressReporter{ctx: ctx, session: session, token: *params.Meta.ProgressToken}
+	return context.WithValue(ctx, progressKey{}, reporter)
+}
+
+// ReportProgress sends notifications/progress for the request handled with
+// ctx. progress must increase with every call; values that do not are not
+// sent. total is zero when unknown. It does nothing when the client did not
+// ask for progress, and once the request has completed.
+func ReportProgress(ctx context.Context, progress, total float64, message string) error {
+	reporter, ok := ctx.Value(progressKey{}).(*progressReporter)
+	if !ok {
+		return nil
+	}
+	return reporter.report(progress, total, message)
+}
+
+// report sends a progress notification
+func (r *progressReporter) report(progress, total float64, message string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.ctx.Err() != nil || (r.last != nil && progress <= *r.last) {
+		return nil
+	}
+	r.last = &progress
+
+	if !r.session.Supports(types.FeatureProgressMessage) {
+		message = ""
+	}
+	return notify(r.ctx, r.session, types.NotificationProgress, types.ProgressNotification{
+		ProgressToken: r.token,
+		Progress:      progress,
+		Total:         total,
+		Message:       message,
+	})
+}
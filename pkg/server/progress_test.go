@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+)
+
+func TestServer_ReportProgress(t *testing.T) {
+	tests := []struct {
+		name     string
+		version  string
+		params   string
+		expected []types.ProgressNotification
+	}{
+		{
+			name:    "with token",
+			version: types.ProtocolVersion20250618,
+			params:  `{"name":"slow","_meta":{"progressToken":"p1"}}`,
+			expected: []types.ProgressNotification{
+				{Progress: 1, Total: 3, Message: "one"},
+				{Progress: 3, Total: 3, Message: "three"},
+			},
+		},
+		{
+			name:    "numeric token",
+			version: types.ProtocolVersion20250618,
+			params:  `{"name":"slow","_meta":{"progressToken":7}}`,
+			expected: []types.ProgressNotification{
+				{Progress: 1, Total: 3, Message: "one"},
+				{Progress: 3, Total: 3, Message: "three"},
+			},
+		},
+		{
+			name:    "message not supported",
+			version: types.ProtocolVersion20241105,
+			params:  `{"name":"slow","_meta":{"progressToken":"p1"}}`,
+			expected: []types.ProgressNotification{
+				{Progress: 1, Total: 3},
+				{Progress: 3, Total: 3},
+			},
+		},
+		{
+			name:    "without token",
+			version: types.ProtocolVersion20250618,
+			params:  `{"name":"slow"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, d := newTestDispatcher(t)
+			require.NoError(t, srv.AddTool(types.Tool{Name: "slow"}, func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+				require.NoError(t, ReportProgress(ctx, 1, 3, "one"))
+				// Progress that does not increase is not sent
+				require.NoError(t, ReportProgress(ctx, 1, 3, "again"))
+				require.NoError(t, ReportProgress(ctx, 3, 3, "three"))
+				return "done", nil
+			}))
+
+			notifier := &recordingNotifier{}
+			conn := NewConnection()
+			conn.SetNotifier(notifier)
+			ctx := WithConnection(context.Background(), conn)
+			dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"`+tt.version+`"}}`)
+			dispatch(t, ctx, d, `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+
+			resp := dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":`+tt.params+`}`)
+			require.Nil(t, resp.Error)
+
+			var got []types.ProgressNotification
+			for _, notification := range notifier.notifications {
+				require.Equal(t, types.NotificationProgress, notification.Method)
+				var progress types.ProgressNotification
+				require.NoError(t, json.Unmarshal(notification.Params, &progress))
+				assert.False(t, progress.ProgressToken.IsZero())
+				progress.ProgressToken = jsonrpc.ID{}
+				got = append(got, progress)
+			}
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+)
+
+// DefaultClientRequestTimeout bounds how long requests sent to clients wait
+// for an answer, unless Options.ClientRequestTimeout says otherwise
+const DefaultClientRequestTimeout = time.Minute
+
+// ErrUnsupportedByClient is returned when sending a request the client did
+// not declare the capability for
+var ErrUnsupportedByClient = errors.New("not supported by the client")
+
+// RequestSender is implemented by the notifiers of transports that can also
+// deliver requests to the client, such as sampling requests
+type RequestSender interface {
+	SendRequest(ctx context.Context, request *jsonrpc.Request) error
+}
+
+// CreateMessage asks the client to sample a language model. The client must
+// have declared the sampling capability.
+func (s *Session) CreateMessage(ctx context.Context, req *types.CreateMessageRequest) (*types.CreateMessageResponse, error) {
+	if !s.CheckClientCapability(types.ClientCapabilities{Sampling: &types.SamplingCapability{}}) {
+		return nil, errors.Wrap(ErrUnsupportedByClient, "sampling")
+	}
+
+	var resp types.CreateMessageResponse
+	if err := s.request(ctx, types.MethodSamplingCreateMessage, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// request sends a request to the client of the session and decodes the
+// result into result. It fails once ctx is done or the client request
+// timeout has passed, in which case the client is told the request was
+// cancelled.
+func (s *Session) request(ctx context.Context, method string, params, result interface{}) error {
+	if !s.IsInitialized() {
+		return errors.New("cannot send " + method + " before the client has completed initialization")
+	}
+	sender := s.requestSender(ctx)
+	if sender == nil {
+		return errors.Wrap(ErrNoNotifier, "cannot send "+method)
+	}
+
+	s.mu.RLock()
+	timeout := s.requestTimeout
+	s.mu.RUnlock()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	id := jsonrpc.NewIntID(atomic.AddInt64(&s.nextRequestID, 1))
+	req, err := jsonrpc.NewRequest(id, method, params)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode "+method)
+	}
+
+	ch := make(chan *jsonrpc.Response, 1)
+	key := id.String()
+	s.pendingMu.Lock()
+	if s.pending == nil {
+		s.pending = make(map[string]chan *jsonrpc.Response)
+	}
+	s.pending[key] = ch
+	s.pendingMu.Unlock()
+	defer func() {
+		s.pendingMu.Lock()
+		delete(s.pending, key)
+		s.pendingMu.Unlock()
+	}()
+
+	if err := sender.SendRequest(ctx, req); err != nil {
+		return errors.Wrap(err, "failed to send "+method)
+	}
+
+	select {
+	case resp := <-ch:
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if result == nil {
+			return nil
+		}
+		if err := json.Unmarshal(resp.Result, result); err != nil {
+			return errors.Wrap(err, "failed to decode result of "+method)
+		}
+		return nil
+	case <-ctx.Done():
+		// The client may still be working on it, tell it to stop
+		cancelled := types.CancelledNotification{RequestID: id, Reason: ctx.Err().Error()}
+		_ = notify(context.WithoutCancel(ctx), s, types.NotificationCancelled, cancelled)
+		return errors.Wrap(ctx.Err(), method)
+	case <-s.Done():
+		return ErrSessionClosed
+	}
+}
+
+// requestSender returns how requests reach the client: the notifier of the
+// request handled with ctx if it belongs to the session and can send
+// requests, or else that of the session
+func (s *Session) requestSender(ctx context.Context) RequestSender {
+	if notifier, ok := ctx.Value(notifierKey{}).(RequestSender); ok {
+		if session, ok := SessionFromContext(ctx); ok && session == s {
+			return notifier
+		}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	sender, _ := s.notifier.(RequestSender)
+	return sender
+}
+
+// resolve hands a response from the client to the request waiting for it.
+// It reports whether one was.
+func (s *Session) resolve(resp *jsonrpc.Response) bool {
+	key := resp.ID.String()
+
+	s.pendingMu.Lock()
+	ch, ok := s.pending[key]
+	delete(s.pending, key)
+	s.pendingMu.Unlock()
+
+	if ok {
+		ch <- resp
+	}
+	return ok
+}
@@ -0,0 +1,110 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+)
+
+// answeringNotifier plays the client side of a connection, answering the
+// requests of the server through the dispatcher
+type answeringNotifier struct {
+	recordingNotifier
+	t      *testing.T
+	ctx    context.Context
+	d      *Dispatcher
+	answer func(req *jsonrpc.Request) string
+}
+
+func (n *answeringNotifier) SendRequest(_ context.Context, req *jsonrpc.Request) error {
+	if n.answer != nil {
+		go dispatch(n.t, n.ctx, n.d, n.answer(req))
+	}
+	return nil
+}
+
+// initializeClient returns the session of a new connection whose client
+// declared the given capabilities and answers requests with answer
+func initializeClient(t *testing.T, d *Dispatcher, capabilities string, answer func(req *jsonrpc.Request) string) (*Session, *answeringNotifier) {
+	conn := NewConnection()
+	ctx := WithConnection(context.Background(), conn)
+	notifier := &answeringNotifier{t: t, ctx: ctx, d: d, answer: answer}
+	conn.SetNotifier(notifier)
+
+	resp := dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":"init","method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":`+capabilities+`}}`)
+	require.Nil(t, resp.Error)
+	require.Nil(t, dispatch(t, ctx, d, `{"jsonrpc":"2.0","method":"notifications/initialized"}`))
+	return conn.Session(), notifier
+}
+
+func TestSession_CreateMessage(t *testing.T) {
+	_, d := newTestDispatcher(t)
+
+	var got types.CreateMessageRequest
+	session, _ := initializeClient(t, d, `{"sampling":{}}`, func(req *jsonrpc.Request) string {
+		require.Equal(t, types.MethodSamplingCreateMessage, req.Method)
+		require.NoError(t, json.Unmarshal(req.Params, &got))
+		return `{"jsonrpc":"2.0","id":` + req.ID.String() + `,"result":{"role":"assistant","content":{"type":"text","text":"Paris"},"model":"test-model","stopReason":"endTurn"}}`
+	})
+
+	resp, err := session.CreateMessage(context.Background(), &types.CreateMessageRequest{
+		Messages: []types.SamplingMessage{
+			{Role: "user", Content: types.NewTextContent("What is the capital of France?")},
+		},
+		MaxTokens: 100,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "assistant", resp.Role)
+	assert.Equal(t, types.NewTextContent("Paris"), resp.Content)
+	assert.Equal(t, "test-model", resp.Model)
+	assert.Equal(t, types.StopReasonEndTurn, resp.StopReason)
+
+	require.Len(t, got.Messages, 1)
+	assert.Equal(t, types.NewTextContent("What is the capital of France?"), got.Messages[0].Content)
+	assert.Equal(t, 100, got.MaxTokens)
+}
+
+func TestSession_CreateMessageErrors(t *testing.T) {
+	req := &types.CreateMessageRequest{
+		Messages:  []types.SamplingMessage{{Role: "user", Content: types.NewTextContent("hi")}},
+		MaxTokens: 10,
+	}
+
+	t.Run("capability not declared", func(t *testing.T) {
+		_, d := newTestDispatcher(t)
+		session, _ := initializeClient(t, d, `{}`, nil)
+
+		_, err := session.CreateMessage(context.Background(), req)
+		assert.Equal(t, ErrUnsupportedByClient, errors.Cause(err))
+	})
+
+	t.Run("error response", func(t *testing.T) {
+		_, d := newTestDispatcher(t)
+		session, _ := initializeClient(t, d, `{"sampling":{}}`, func(req *jsonrpc.Request) string {
+			return `{"jsonrpc":"2.0","id":` + req.ID.String() + `,"error":{"code":-1,"message":"User rejected sampling request"}}`
+		})
+
+		_, err := session.CreateMessage(context.Background(), req)
+		var rpcErr *jsonrpc.ErrorObject
+		require.ErrorAs(t, err, &rpcErr)
+		assert.Equal(t, -1, rpcErr.Code)
+		assert.Equal(t, "User rejected sampling request", rpcErr.Message)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		_, d := newTestDispatcherWithOptions(t, &Options{ClientRequestTimeout: 50 * time.Millisecond})
+		session, notifier := initializeClient(t, d, `{"sampling":{}}`, nil)
+
+		_, err := session.CreateMessage(context.Background(), req)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, []string{types.NotificationCancelled}, notifier.methods())
+	})
+}
@@ -0,0 +1,256 @@
+package server
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+	"github.com/harriteja/mcp-go-sdk/pkg/uritemplate"
+)
+
+// ResourceHandler reads a resource added with AddResource and returns its
+// data and MIME type. An empty MIME type stands for that of the resource.
+type ResourceHandler func(ctx context.Context, uri string) ([]byte, string, error)
+
+// ResourceTemplateHandler reads a resource matching a template added with
+// AddResourceTemplate. vars holds the values of the template variables in
+// uri. An empty MIME type stands for that of the template.
+type ResourceTemplateHandler func(ctx context.Context, uri string, vars map[string]string) ([]byte, string, error)
+
+// registeredResource is a resource added with AddResource
+type registeredResource struct {
+	resource types.Resource
+	handler  ResourceHandler
+}
+
+// registeredTemplate is a resource template added with AddResourceTemplate
+type registeredTemplate struct {
+	template types.ResourceTemplate
+	compiled *uritemplate.Template
+	handler  ResourceTemplateHandler
+}
+
+// resourceReader reads the resource a URI was routed to
+type resourceReader func(ctx context.Context) ([]byte, string, error)
+
+// resourceRegistry holds the resources and resource templates added with
+// AddResource and AddResourceTemplate, in the order they were added
+type resourceRegistry struct {
+	mu        sync.RWMutex
+	resources map[string]*registeredResource
+	uris      []string
+	templates []*registeredTemplate
+}
+
+// add adds or replaces a resource
+func (r *resourceRegistry) add(resource *registeredResource) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	uri := resource.resource.URI
+	if r.resources == nil {
+		r.resources = make(map[string]*registeredResource)
+	}
+	if _, ok := r.resources[uri]; !ok {
+		r.uris = append(r.uris, uri)
+	}
+	r.resources[uri] = resource
+}
+
+// remove removes a resource and reports whether it was registered
+func (r *resourceRegistry) remove(uri string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.resources[uri]; !ok {
+		return false
+	}
+	delete(r.resources, uri)
+	for i, u := range r.uris {
+		if u == uri {
+			r.uris = append(r.uris[:i], r.uris[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// addTemplate adds a template, or replaces the one with the same URI
+// template in place
+func (r *resourceRegistry) addTemplate(template *registeredTemplate) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, t := range r.templates {
+		if t.template.URITemplate == template.template.URITemplate {
+			r.templates[i] = template
+			return
+		}
+	}
+	r.templates = append(r.templates, template)
+}
+
+// removeTemplate removes a template and reports whether it was registered
+func (r *resourceRegistry) removeTemplate(uriTemplate string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, t := range r.templates {
+		if t.template.URITemplate == uriTemplate {
+			r.templates = append(r.templates[:i], r.templates[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// match routes a URI to its resource. Resources added with AddResource come
+// first. Among the templates matching the URI, the one with the most literal
+// characters wins, and the earliest added one on ties.
+func (r *resourceRegistry) match(uri string) (resourceReader, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if res, ok := r.resources[uri]; ok {
+		return func(ctx context.Context) ([]byte, string, error) {
+			data, mimeType, err := res.handler(ctx, uri)
+			if mimeType == "" {
+				mimeType = res.resource.MimeType
+			}
+			return data, mimeType, err
+		}, true
+	}
+
+	var (
+		best *registeredTemplate
+		vars map[string]string
+	)
+	for _, t := range r.templates {
+		if best != nil && t.compiled.Specificity() <= best.compiled.Specificity() {
+			continue
+		}
+		if matched, ok := t.compiled.Match(uri); ok {
+			best, vars = t, matched
+		}
+	}
+	if best == nil {
+		return nil, false
+	}
+	return func(ctx context.Context) ([]byte, string, error) {
+		data, mimeType, err := best.handler(ctx, uri, vars)
+		if mimeType == "" {
+			mimeType = best.template.MimeType
+		}
+		return data, mimeType, err
+	}, true
+}
+
+// list returns the registered resources
+func (r *resourceRegistry) list() []types.Resource {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	resources := make([]types.Resource, 0, len(r.uris))
+	for _, uri := range r.uris {
+		resources = append(resources, r.resources[uri].resource)
+	}
+	return resources
+}
+
+// listTemplates returns the registered resource templates
+func (r *resourceRegistry) listTemplates() []types.ResourceTemplate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	templates := make([]types.ResourceTemplate, 0, len(r.templates))
+	for _, t := range r.templates {
+		templates = append(templates, t.template)
+	}
+	return templates
+}
+
+// len returns the number of registered resources and templates
+func (r *resourceRegistry) len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.uris) + len(r.templates)
+}
+
+// AddResource registers a resource with a fixed URI and its handler. The
+// resource is listed by resources/list and reads of its URI are routed to
+// the handler, ahead of resource templates and any OnReadResource handler.
+// Adding a resource with the URI of a registered one replaces it.
+// Initialized sessions are sent notifications/resources/list_changed.
+func (s *Server) AddResource(resource types.Resource, handler ResourceHandler) error {
+	if resource.URI == "" {
+		return errors.New("resource URI is required")
+	}
+	if resource.Name == "" {
+		return errors.New("resource name is required")
+	}
+	if handler == nil {
+		return errors.New("resource handler is required")
+	}
+
+	s.resources.add(&registeredResource{resource: resource, handler: handler})
+	s.broadcast(context.Background(), types.NotificationResourcesListChanged, nil)
+	return nil
+}
+
+// AddResourceTemplate registers a resource template and its handler. The
+// URI template, like file:///logs/{date}/{level}, is compiled here and may
+// use the expressions of RFC 6570 levels 1 to 3. The template is listed by
+// resources/templates/list and reads of URIs matching it are routed to the
+// handler with the values of its variables. When several templates match a
+// URI, the one with the most literal characters wins, and the earliest added
+// one on ties. Adding a template with the URI template of a registered one
+// replaces it. Initialized sessions are sent
+// notifications/resources/list_changed.
+func (s *Server) AddResourceTemplate(template types.ResourceTemplate, handler ResourceTemplateHandler) error {
+	if template.Name == "" {
+		return errors.New("resource template name is required")
+	}
+	if handler == nil {
+		return errors.New("resource template handler is required")
+	}
+	compiled, err := uritemplate.Parse(template.URITemplate)
+	if err != nil {
+		return errors.Wrap(err, "invalid URI template of resource template "+template.Name)
+	}
+
+	s.resources.addTemplate(&registeredTemplate{template: template, compiled: compiled, handler: handler})
+	s.broadcast(context.Background(), types.NotificationResourcesListChanged, nil)
+	return nil
+}
+
+// RemoveResource removes a resource registered with AddResource. It reports
+// whether the resource was registered; if so, initialized sessions are sent
+// notifications/resources/list_changed.
+func (s *Server) RemoveResource(uri string) bool {
+	if !s.resources.remove(uri) {
+		return false
+	}
+	s.broadcast(context.Background(), types.NotificationResourcesListChanged, nil)
+	return true
+}
+
+// RemoveResourceTemplate removes a resource template registered with
+// AddResourceTemplate, see RemoveResource
+func (s *Server) RemoveResourceTemplate(uriTemplate string) bool {
+	if !s.resources.removeTemplate(uriTemplate) {
+		return false
+	}
+	s.broadcast(context.Background(), types.NotificationResourcesListChanged, nil)
+	return true
+}
+
+// unknownResourceError is returned for reads of a resource that does not
+// exist
+func unknownResourceError(uri string) error {
+	err := jsonrpc.NewError(types.CodeResourceNotFound, "resource not found: "+uri)
+	err.Data = map[string]interface{}{"uri": uri}
+	return err
+}
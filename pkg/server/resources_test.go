@@ -0,0 +1,148 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+)
+
+func TestServer_ResourceRegistry(t *testing.T) {
+	srv, d := newTestDispatcher(t)
+
+	notifier := &recordingNotifier{}
+	conn := NewConnection()
+	conn.SetNotifier(notifier)
+	ctx := WithConnection(context.Background(), conn)
+	dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18"}}`)
+	dispatch(t, ctx, d, `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+
+	readme := func(ctx context.Context, uri string) ([]byte, string, error) {
+		return []byte("# Readme"), "", nil
+	}
+	logs := func(ctx context.Context, uri string, vars map[string]string) ([]byte, string, error) {
+		return []byte(vars["date"] + " " + vars["level"]), "", nil
+	}
+	require.NoError(t, srv.AddResource(types.Resource{URI: "file:///README.md", Name: "readme", MimeType: "text/markdown"}, readme))
+	require.NoError(t, srv.AddResourceTemplate(types.ResourceTemplate{URITemplate: "file:///logs/{date}/{level}", Name: "logs", MimeType: "text/plain"}, logs))
+	assert.Error(t, srv.AddResource(types.Resource{Name: "no uri"}, readme))
+	assert.Error(t, srv.AddResource(types.Resource{URI: "file:///a", Name: "nil"}, nil))
+	assert.Error(t, srv.AddResourceTemplate(types.ResourceTemplate{URITemplate: "file:///{bad", Name: "bad"}, logs))
+
+	assert.Equal(t, &types.ResourcesCapability{Subscribe: true, ListChanged: true}, srv.Capabilities().Resources)
+
+	resp := dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":2,"method":"resources/list"}`)
+	require.Nil(t, resp.Error)
+	assert.JSONEq(t, `{"resources":[{"uri":"file:///README.md","name":"readme","mimeType":"text/markdown"}]}`, string(resp.Result))
+
+	resp = dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":3,"method":"resources/templates/list"}`)
+	require.Nil(t, resp.Error)
+	assert.JSONEq(t, `{"resourceTemplates":[{"uriTemplate":"file:///logs/{date}/{level}","name":"logs","mimeType":"text/plain"}]}`, string(resp.Result))
+
+	// The MIME types default to those of the resource and the template
+	resp = dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":4,"method":"resources/read","params":{"uri":"file:///README.md"}}`)
+	require.Nil(t, resp.Error)
+	assert.JSONEq(t, `{"contents":[{"uri":"file:///README.md","mimeType":"text/markdown","text":"# Readme"}]}`, string(resp.Result))
+
+	resp = dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":5,"method":"resources/read","params":{"uri":"file:///logs/2024-01-02/error"}}`)
+	require.Nil(t, resp.Error)
+	assert.JSONEq(t, `{"contents":[{"uri":"file:///logs/2024-01-02/error","mimeType":"text/plain","text":"2024-01-02 error"}]}`, string(resp.Result))
+
+	resp = dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":6,"method":"resources/read","params":{"uri":"file:///missing"}}`)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, types.CodeResourceNotFound, resp.Error.Code)
+	assert.Equal(t, map[string]interface{}{"uri": "file:///missing"}, resp.Error.Data)
+
+	assert.True(t, srv.RemoveResource("file:///README.md"))
+	assert.False(t, srv.RemoveResource("file:///README.md"))
+	assert.True(t, srv.RemoveResourceTemplate("file:///logs/{date}/{level}"))
+	assert.False(t, srv.RemoveResourceTemplate("file:///logs/{date}/{level}"))
+
+	// Every change is announced once, failed additions and removals are not
+	assert.Equal(t, []string{
+		types.NotificationResourcesListChanged,
+		types.NotificationResourcesListChanged,
+		types.NotificationResourcesListChanged,
+		types.NotificationResourcesListChanged,
+	}, notifier.methods())
+}
+
+func TestServer_ResourceTemplatePrecedence(t *testing.T) {
+	srv, err := New(&Options{Logger: types.NewNoOpLogger()})
+	require.NoError(t, err)
+
+	handler := func(name string) ResourceTemplateHandler {
+		return func(ctx context.Context, uri string, vars map[string]string) ([]byte, string, error) {
+			return []byte(name), "text/plain", nil
+		}
+	}
+	require.NoError(t, srv.AddResourceTemplate(types.ResourceTemplate{URITemplate: "file:///{+path}", Name: "files"}, handler("files")))
+	require.NoError(t, srv.AddResourceTemplate(types.ResourceTemplate{URITemplate: "file:///logs/{date}/{level}", Name: "logs"}, handler("logs")))
+	require.NoError(t, srv.AddResourceTemplate(types.ResourceTemplate{URITemplate: "file:///logs/{date}/error", Name: "errors"}, handler("errors")))
+	require.NoError(t, srv.AddResourceTemplate(types.ResourceTemplate{URITemplate: "file:///logs/{day}/{severity}", Name: "same"}, handler("same")))
+	require.NoError(t, srv.AddResource(types.Resource{URI: "file:///logs/today/error", Name: "today"}, func(ctx context.Context, uri string) ([]byte, string, error) {
+		return []byte("today"), "text/plain", nil
+	}))
+
+	tests := map[string]string{
+		// Fixed URIs come first
+		"file:///logs/today/error": "today",
+		// Then the template with the most literal characters
+		"file:///logs/2024-01-02/error": "errors",
+		// And the earliest added one on ties
+		"file:///logs/2024-01-02/info": "logs",
+		"file:///etc/hosts":            "files",
+	}
+	for uri, want := range tests {
+		data, _, err := srv.ReadResource(context.Background(), uri)
+		require.NoError(t, err, uri)
+		assert.Equal(t, want, string(data), uri)
+	}
+
+	// Adding a template again replaces it in place
+	require.NoError(t, srv.AddResourceTemplate(types.ResourceTemplate{URITemplate: "file:///logs/{date}/{level}", Name: "logs v2"}, handler("logs v2")))
+	data, _, err := srv.ReadResource(context.Background(), "file:///logs/2024-01-02/info")
+	require.NoError(t, err)
+	assert.Equal(t, "logs v2", string(data))
+}
+
+func TestServer_ResourceRegistryWithHandlers(t *testing.T) {
+	srv, err := New(&Options{Logger: types.NewNoOpLogger()})
+	require.NoError(t, err)
+
+	srv.OnListResources(func(ctx context.Context) ([]types.Resource, error) {
+		return []types.Resource{{URI: "legacy://a", Name: "legacy"}}, nil
+	})
+	srv.OnReadResource(func(ctx context.Context, uri string) ([]byte, string, error) {
+		return []byte("legacy " + uri), "text/plain", nil
+	})
+	require.NoError(t, srv.AddResource(types.Resource{URI: "file:///added", Name: "added"}, func(ctx context.Context, uri string) ([]byte, string, error) {
+		return []byte("added"), "text/plain", nil
+	}))
+	require.NoError(t, srv.AddResourceTemplate(types.ResourceTemplate{URITemplate: "legacy://{id}/info", Name: "info"}, func(ctx context.Context, uri string, vars map[string]string) ([]byte, string, error) {
+		return []byte("info " + vars["id"]), "text/plain", nil
+	}))
+
+	resources, err := srv.ListResources(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []types.Resource{{URI: "file:///added", Name: "added"}, {URI: "legacy://a", Name: "legacy"}}, resources)
+
+	data, _, err := srv.ReadResource(context.Background(), "file:///added")
+	require.NoError(t, err)
+	assert.Equal(t, "added", string(data))
+
+	data, _, err = srv.ReadResource(context.Background(), "legacy://a")
+	require.NoError(t, err)
+	assert.Equal(t, "legacy legacy://a", string(data))
+
+	// Templates do not match empty variables, which leaves them to the handler
+	data, _, err = srv.ReadResource(context.Background(), "legacy://a/info")
+	require.NoError(t, err)
+	assert.Equal(t, "info a", string(data))
+	data, _, err = srv.ReadResource(context.Background(), "legacy:///info")
+	require.NoError(t, err)
+	assert.Equal(t, "legacy legacy:///info", string(data))
+}
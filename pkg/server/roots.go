@@ -0,0 +1,67 @@
+package server
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+)
+
+// ListRoots returns the roots the client grants the server access to. The
+// client must have declared the roots capability. When it also declared
+// that it notifies changes the roots are cached until it does, otherwise
+// they are requested on every call.
+func (s *Session) ListRoots(ctx context.Context) ([]types.Root, error) {
+	if !s.CheckClientCapability(types.ClientCapabilities{Roots: &types.RootsCapability{}}) {
+		return nil, errors.Wrap(ErrUnsupportedByClient, "roots")
+	}
+	cacheable := s.CheckClientCapability(types.ClientCapabilities{Roots: &types.RootsCapability{ListChanged: true}})
+
+	s.rootsMu.Lock()
+	if s.rootsCached {
+		roots := s.roots
+		s.rootsMu.Unlock()
+		return roots, nil
+	}
+	version := s.rootsVersion
+	s.rootsMu.Unlock()
+
+	var result types.ListRootsResult
+	if err := s.request(ctx, types.MethodRootsList, nil, &result); err != nil {
+		return nil, err
+	}
+	if result.Roots == nil {
+		result.Roots = []types.Root{}
+	}
+
+	if cacheable {
+		s.rootsMu.Lock()
+		if s.rootsVersion == version {
+			s.roots = result.Roots
+			s.rootsCached = true
+		}
+		s.rootsMu.Unlock()
+	}
+	return result.Roots, nil
+}
+
+// invalidateRoots drops the cached roots after the client changed them
+func (s *Session) invalidateRoots() {
+	s.rootsMu.Lock()
+	defer s.rootsMu.Unlock()
+	s.roots = nil
+	s.rootsCached = false
+	s.rootsVersion++
+}
+
+// RootsListChanged handles notifications that the roots of the client
+// changed, so the next Session.ListRoots asks the client again
+func (s *Server) RootsListChanged(ctx context.Context, req *types.RootsListChangedNotification) error {
+	session, ok := SessionFromContext(ctx)
+	if !ok {
+		return errors.New("roots changed outside of a session")
+	}
+	session.invalidateRoots()
+	return nil
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+)
+
+func TestSession_ListRoots(t *testing.T) {
+	tests := []struct {
+		name         string
+		capabilities string
+		requests     int64
+	}{
+		{
+			name:         "cached until changed",
+			capabilities: `{"roots":{"listChanged":true}}`,
+			requests:     2,
+		},
+		{
+			name:         "changes not notified",
+			capabilities: `{"roots":{"listChanged":false}}`,
+			requests:     3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, d := newTestDispatcher(t)
+
+			var requests int64
+			session, notifier := initializeClient(t, d, tt.capabilities, func(req *jsonrpc.Request) string {
+				require.Equal(t, types.MethodRootsList, req.Method)
+				// Every answer holds one more root than the previous one
+				n := atomic.AddInt64(&requests, 1)
+				var result types.ListRootsResult
+				for i := int64(1); i <= n; i++ {
+					result.Roots = append(result.Roots, types.Root{URI: fmt.Sprintf("file:///project%d", i)})
+				}
+				data, err := json.Marshal(result)
+				require.NoError(t, err)
+				return `{"jsonrpc":"2.0","id":` + req.ID.String() + `,"result":` + string(data) + `}`
+			})
+
+			roots, err := session.ListRoots(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, []types.Root{{URI: "file:///project1"}}, roots)
+
+			roots, err = session.ListRoots(context.Background())
+			require.NoError(t, err)
+			assert.Len(t, roots, int(atomic.LoadInt64(&requests)))
+
+			dispatch(t, notifier.ctx, d, `{"jsonrpc":"2.0","method":"notifications/roots/list_changed"}`)
+			roots, err = session.ListRoots(context.Background())
+			require.NoError(t, err)
+			assert.Len(t, roots, int(atomic.LoadInt64(&requests)))
+			assert.Equal(t, tt.requests, atomic.LoadInt64(&requests))
+		})
+	}
+}
+
+func TestSession_ListRootsUnsupported(t *testing.T) {
+	_, d := newTestDispatcher(t)
+	session, _ := initializeClient(t, d, `{}`, nil)
+
+	_, err := session.ListRoots(context.Background())
+	assert.Equal(t, ErrUnsupportedByClient, errors.Cause(err))
+}
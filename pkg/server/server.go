@@ -8,6 +8,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
+	"github.com/harriteja/mcp-go-sdk/pkg/keepalive"
 	"github.com/harriteja/mcp-go-sdk/pkg/logger"
 	"github.com/harriteja/mcp-go-sdk/pkg/types"
 )
@@ -24,17 +26,49 @@ type Server struct {
 	instructions string
 	logger       types.Logger
 
+	protocolVersions  []string
+	legacyMethodNames bool
+	capabilitiesFunc  CapabilitiesFunc
+	outputValidation  OutputValidation
+	pageSize          int
+	cursorSecret      []byte
+	requestTimeout    time.Duration
+	keepAlive         *keepalive.Keeper
+
 	// Handlers
 	listToolsHandler             HandlerFunc[[]types.Tool]
 	callToolHandler              func(context.Context, string, map[string]interface{}) (interface{}, error)
 	listPromptsHandler           HandlerFunc[[]types.Prompt]
-	getPromptHandler             func(context.Context, string, map[string]interface{}) (*types.Prompt, error)
+	getPromptHandler             func(context.Context, string, map[string]interface{}) (*types.GetPromptResult, error)
 	listResourcesHandler         HandlerFunc[[]types.Resource]
 	readResourceHandler          func(context.Context, string) ([]byte, string, error)
 	listResourceTemplatesHandler HandlerFunc[[]types.ResourceTemplate]
 
+	// Page handlers serve lists page by page, instead of the handlers above
+	listToolsPageHandler             PageFunc[types.Tool]
+	listPromptsPageHandler           PageFunc[types.Prompt]
+	listResourcesPageHandler         PageFunc[types.Resource]
+	listResourceTemplatesPageHandler PageFunc[types.ResourceTemplate]
+
+	// tools holds the tools added with AddTool
+	tools toolRegistry
+
+	// resources holds the resources and templates added with AddResource
+	// and AddResourceTemplate
+	resources resourceRegistry
+
+	// completers holds the completers of prompt arguments and resource
+	// template variables
+	completers map[completionKey]Completer
+
 	// Session management
-	sessions map[string]*Session
+	sessions SessionStore
+
+	// inflight tracks the requests currently being handled
+	inflight inflightRequests
+
+	// subscriptions records the resources sessions subscribed to
+	subscriptions subscriptions
 }
 
 // Options represents server configuration options
@@ -44,6 +78,51 @@ type Options struct {
 	Instructions string
 	Logger       types.Logger
 	ServerInfo   types.Implementation
+
+	// SessionStore keeps the sessions. Defaults to a MemorySessionStore with
+	// default options.
+	SessionStore SessionStore
+
+	// ProtocolVersions lists the protocol revisions the server accepts.
+	// Defaults to types.SupportedProtocolVersions.
+	ProtocolVersions []string
+
+	// LegacyMethodNames additionally accepts the camelCase method names
+	// (listTools, callTool, ...) and payload shapes used before the SDK
+	// adopted the spec names, so older clients keep working while they
+	// migrate. Messages framed like theirs, without jsonrpc member or ID,
+	// are answered with a bare {"result": ...} or {"error": ...} object.
+	// WebSocket frames without a jsonrpc member still go to the handlers of
+	// their type.
+	LegacyMethodNames bool
+
+	// Capabilities, if set, can amend the capabilities derived from the
+	// registered handlers, for example to advertise experimental features
+	Capabilities CapabilitiesFunc
+
+	// OutputValidation tells what to do when a tool added with AddTool
+	// returns structured content not matching its output schema. Defaults
+	// to failing the call.
+	OutputValidation OutputValidation
+
+	// PageSize is the number of items per page of the lists served from
+	// the registries and from handlers returning whole lists. Zero serves
+	// them in a single page.
+	PageSize int
+
+	// CursorSecret signs the cursors of paginated lists. Servers behind a
+	// load balancer must share it. Defaults to a random key.
+	CursorSecret []byte
+
+	// ClientRequestTimeout bounds how long requests sent to clients, such
+	// as sampling requests, wait for an answer. Defaults to
+	// DefaultClientRequestTimeout.
+	ClientRequestTimeout time.Duration
+
+	// KeepAlive pings the clients of initialized sessions at an interval
+	// and closes the sessions of clients that stop answering. Disabled by
+	// default.
+	KeepAlive keepalive.Options
 }
 
 // New creates a new MCP server instance
@@ -65,13 +144,50 @@ func New(opts *Options) (*Server, error) {
 		version = opts.ServerInfo.Version
 	}
 
-	return &Server{
+	protocolVersions := opts.ProtocolVersions
+	if len(protocolVersions) == 0 {
+		protocolVersions = types.SupportedProtocolVersions
+	}
+
+	store := opts.SessionStore
+	if store == nil {
+		store = NewMemorySessionStore(MemorySessionStoreOptions{})
+	}
+
+	requestTimeout := opts.ClientRequestTimeout
+	if requestTimeout <= 0 {
+		requestTimeout = DefaultClientRequestTimeout
+	}
+
+	cursorSecret := opts.CursorSecret
+	if len(cursorSecret) == 0 {
+		cursorSecret = newCursorSecret()
+	}
+
+	keeper, err := keepalive.New(opts.KeepAlive)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to set up keepalive")
+	}
+
+	srv := &Server{
 		name:         name,
 		version:      version,
 		instructions: opts.Instructions,
 		logger:       log,
-		sessions:     make(map[string]*Session),
-	}, nil
+		sessions:     store,
+
+		protocolVersions:  protocolVersions,
+		legacyMethodNames: opts.LegacyMethodNames,
+		capabilitiesFunc:  opts.Capabilities,
+		outputValidation:  opts.OutputValidation,
+		pageSize:          opts.PageSize,
+		cursorSecret:      cursorSecret,
+		requestTimeout:    requestTimeout,
+		keepAlive:         keeper,
+	}
+	store.OnClose(srv.sessionClosed)
+
+	return srv, nil
 }
 
 // OnListTools registers a handler for listing tools
@@ -95,8 +211,9 @@ func (s *Server) OnListPrompts(handler HandlerFunc[[]types.Prompt]) {
 	s.listPromptsHandler = handler
 }
 
-// OnGetPrompt registers a handler for getting a prompt
-func (s *Server) OnGetPrompt(handler func(context.Context, string, map[string]interface{}) (*types.Prompt, error)) {
+// OnGetPrompt registers a handler rendering a prompt with its arguments into
+// the messages of a conversation
+func (s *Server) OnGetPrompt(handler func(context.Context, string, map[string]interface{}) (*types.GetPromptResult, error)) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.getPromptHandler = handler
@@ -123,125 +240,331 @@ func (s *Server) OnListResourceTemplates(handler HandlerFunc[[]types.ResourceTem
 	s.listResourceTemplatesHandler = handler
 }
 
-// Initialize handles client initialization
+// OnListToolsPage registers a handler serving the tools page by page. It
+// replaces the OnListTools handler.
+func (s *Server) OnListToolsPage(handler PageFunc[types.Tool]) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listToolsPageHandler = handler
+}
+
+// OnListPromptsPage registers a handler serving the prompts page by page. It
+// replaces the OnListPrompts handler.
+func (s *Server) OnListPromptsPage(handler PageFunc[types.Prompt]) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listPromptsPageHandler = handler
+}
+
+// OnListResourcesPage registers a handler serving the resources page by
+// page. It replaces the OnListResources handler.
+func (s *Server) OnListResourcesPage(handler PageFunc[types.Resource]) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listResourcesPageHandler = handler
+}
+
+// OnListResourceTemplatesPage registers a handler serving the resource
+// templates page by page. It replaces the OnListResourceTemplates handler.
+func (s *Server) OnListResourceTemplatesPage(handler PageFunc[types.ResourceTemplate]) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listResourceTemplatesPageHandler = handler
+}
+
+// Initialize handles client initialization. The new session is bound to
+// the connection carried by ctx, which must not have one yet.
 func (s *Server) Initialize(ctx context.Context, req *types.InitializeRequest) (*types.InitializeResponse, error) {
 	s.logger.Info(ctx, "server", "initialize", "Initializing server")
 
+	conn := connectionFromContext(ctx)
+	if conn != nil && conn.Session() != nil {
+		return nil, jsonrpc.NewError(jsonrpc.CodeInvalidRequest, "session already initialized")
+	}
+
+	// Use the client's protocol version if we speak it, otherwise offer our
+	// latest and let the client decide whether to continue
+	version := types.NegotiateProtocolVersion(req.ProtocolVersion, s.protocolVersions)
+	if version != req.ProtocolVersion {
+		s.logger.Warn(ctx, "server", "initialize", "Client requested unsupported protocol version "+req.ProtocolVersion+", offering "+version)
+	}
+
 	// Create new session
 	sessionID := uuid.New().String()
-	session := NewSession(sessionID, req)
+	negotiated := *req
+	negotiated.ProtocolVersion = version
+	session := NewSession(sessionID, &negotiated)
+	session.requestTimeout = s.requestTimeout
 
-	s.mu.Lock()
-	s.sessions[sessionID] = session
-	s.mu.Unlock()
+	if err := s.sessions.Add(session); err != nil {
+		return nil, errors.Wrap(err, "failed to store session")
+	}
+
+	if conn != nil {
+		conn.Bind(session)
+	}
 
-	// Return server capabilities
 	return &types.InitializeResponse{
-		ProtocolVersion: req.ProtocolVersion,
+		ProtocolVersion: version,
 		ServerInfo: types.Implementation{
 			Name:    s.name,
 			Version: s.version,
 		},
-		Capabilities: types.ServerCapabilities{
-			Tools:     &types.ToolsCapability{},
-			Prompts:   &types.PromptsCapability{},
-			Resources: &types.ResourcesCapability{},
-		},
+		Capabilities: capabilitiesFor(s.Capabilities(), version),
 		Instructions: s.instructions,
 	}, nil
 }
 
-// Initialized handles the notification that the client has completed initialization
+// Initialized handles the notification that the client has completed
+// initialization. From then on the server may send requests to the client.
 func (s *Server) Initialized(ctx context.Context, _ *types.InitializedNotification) error {
-	s.logger.Info(ctx, "server", "initialized", "Client has completed initialization")
+	session, ok := SessionFromContext(ctx)
+	if !ok {
+		return errors.New("initialized notification received before initialize")
+	}
 
-	// This method simply acknowledges that the client has completed initialization
-	// No specific response is needed as per the protocol
+	session.markInitialized()
+	s.logger.Info(ctx, "server", "initialized", "Client has completed initialization of session "+session.ID())
+	if s.keepAlive.Enabled() {
+		go s.keepSessionAlive(session)
+	}
 	return nil
 }
 
-// Ping handles ping requests for health check/connectivity testing
+// Ping handles ping requests from clients checking that the server is alive
 func (s *Server) Ping(ctx context.Context, req *types.PingRequest) (*types.PingResponse, error) {
-	// Respond with the current server timestamp and echo back the client timestamp if provided
-	return &types.PingResponse{
-		Timestamp:       req.Timestamp,
-		ServerTimestamp: time.Now().UnixNano() / int64(time.Millisecond),
-	}, nil
+	return &types.PingResponse{}, nil
 }
 
-// Cancel handles cancellation requests for ongoing operations
-func (s *Server) Cancel(ctx context.Context, req *types.CancelRequest) error {
-	s.logger.Info(ctx, "server", "cancel", "Received cancellation request for request ID: "+req.ID)
+// Cancel handles cancellation notifications for ongoing operations. The
+// context of the cancelled request's handler is cancelled with
+// ErrRequestCancelled and its response is never sent.
+func (s *Server) Cancel(ctx context.Context, req *types.CancelledNotification) error {
+	msg := "Received cancellation request for request ID: " + req.RequestID.String()
+	if req.Reason != "" {
+		msg += " (" + req.Reason + ")"
+	}
+	s.logger.Info(ctx, "server", "cancel", msg)
 
-	// Note: Actual cancellation implementation would depend on how requests are tracked
-	// This is a placeholder implementation
+	// The request may already have completed, which is not an error
+	if !s.inflight.cancel(ctx, req.RequestID) {
+		s.logger.Info(ctx, "server", "cancel", "Request "+req.RequestID.String()+" is not in flight")
+	}
 	return nil
 }
 
-// getSession retrieves a session by ID
-func (s *Server) getSession(sessionID string) (*Session, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+// Session returns the session with the given ID. Transports without a
+// persistent connection use it to bind requests to their session.
+func (s *Server) Session(sessionID string) (*Session, bool) {
+	return s.sessions.Get(sessionID)
+}
 
-	session, ok := s.sessions[sessionID]
-	if !ok {
-		return nil, errors.New("session not found")
+// Sessions returns the open sessions, oldest first
+func (s *Server) Sessions() []*Session {
+	return s.sessions.List()
+}
+
+// CloseSession closes the session with the given ID. Requests still running
+// in the session are cancelled and its connection, if any, has to
+// initialize again.
+func (s *Server) CloseSession(sessionID string) error {
+	if !s.sessions.Remove(sessionID) {
+		return errors.New("session not found")
+	}
+	return nil
+}
+
+// sessionClosed is called by the session store whenever a session ends
+func (s *Server) sessionClosed(session *Session, reason CloseReason) {
+	if !session.close() {
+		return
 	}
-	return session, nil
+	s.inflight.cancelSession(session.ID())
+	s.subscriptions.removeSession(session.ID())
+	s.logger.Info(context.Background(), "server", "session", "Session "+session.ID()+" ended: "+string(reason))
 }
 
-// ListTools handles the list tools request
+// ListTools returns every tool: those added with AddTool, followed by those
+// of the list handler
 func (s *Server) ListTools(ctx context.Context) ([]types.Tool, error) {
-	if s.listToolsHandler == nil {
-		return nil, errors.New("list tools handler not registered")
+	return collect(ctx, s, s.toolSources())
+}
+
+// ListToolsPage returns the page of tools starting at cursor and the cursor
+// of the next page, which is empty on the last page
+func (s *Server) ListToolsPage(ctx context.Context, cursor string) ([]types.Tool, string, error) {
+	return paginate(ctx, s, s.toolSources(), cursor)
+}
+
+// toolSources returns the sources of the tools list
+func (s *Server) toolSources() listSources[types.Tool] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return listSources[types.Tool]{
+		name:     "tools",
+		registry: s.tools.list,
+		all:      s.listToolsHandler,
+		page:     s.listToolsPageHandler,
 	}
-	return s.listToolsHandler(ctx)
 }
 
-// CallTool handles the call tool request
+// CallTool handles the call tool request. Tools added with AddTool take
+// precedence over the OnCallTool handler.
 func (s *Server) CallTool(ctx context.Context, name string, args map[string]interface{}) (interface{}, error) {
-	if s.callToolHandler == nil {
+	if tool, ok := s.tools.get(name); ok {
+		return s.callRegisteredTool(ctx, tool, args)
+	}
+
+	s.mu.RLock()
+	handler := s.callToolHandler
+	s.mu.RUnlock()
+
+	if handler == nil {
+		if s.tools.len() > 0 {
+			return nil, unknownToolError(name)
+		}
 		return nil, errors.New("call tool handler not registered")
 	}
-	return s.callToolHandler(ctx, name, args)
+	return handler(ctx, name, args)
 }
 
-// ListPrompts handles the list prompts request
+// callRegisteredTool calls a tool added with AddTool and validates its
+// structured content
+func (s *Server) callRegisteredTool(ctx context.Context, tool *registeredTool, args map[string]interface{}) (interface{}, error) {
+	value, err := tool.call(ctx, args)
+	if err != nil || tool.output == nil || s.outputValidation == OutputValidationOff {
+		return value, err
+	}
+
+	result, err := toolResult(value)
+	if err != nil {
+		return nil, err
+	}
+	if err := tool.checkOutput(result); err != nil {
+		if s.outputValidation == OutputValidationFail {
+			return nil, err
+		}
+		s.logger.Warn(ctx, "server", "callTool", err.Error())
+	}
+	return result, nil
+}
+
+// ListPrompts returns every prompt
 func (s *Server) ListPrompts(ctx context.Context) ([]types.Prompt, error) {
-	if s.listPromptsHandler == nil {
-		return nil, errors.New("list prompts handler not registered")
+	return collect(ctx, s, s.promptSources())
+}
+
+// ListPromptsPage returns the page of prompts starting at cursor and the
+// cursor of the next page, which is empty on the last page
+func (s *Server) ListPromptsPage(ctx context.Context, cursor string) ([]types.Prompt, string, error) {
+	return paginate(ctx, s, s.promptSources(), cursor)
+}
+
+// promptSources returns the sources of the prompts list
+func (s *Server) promptSources() listSources[types.Prompt] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return listSources[types.Prompt]{
+		name: "prompts",
+		all:  s.listPromptsHandler,
+		page: s.listPromptsPageHandler,
 	}
-	return s.listPromptsHandler(ctx)
 }
 
 // GetPrompt handles the get prompt request
-func (s *Server) GetPrompt(ctx context.Context, name string, args map[string]interface{}) (*types.Prompt, error) {
-	if s.getPromptHandler == nil {
+func (s *Server) GetPrompt(ctx context.Context, name string, args map[string]interface{}) (*types.GetPromptResult, error) {
+	s.mu.RLock()
+	handler := s.getPromptHandler
+	s.mu.RUnlock()
+
+	if handler == nil {
 		return nil, errors.New("get prompt handler not registered")
 	}
-	return s.getPromptHandler(ctx, name, args)
+	result, err := handler(ctx, name, args)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		result = &types.GetPromptResult{}
+	}
+	if result.Messages == nil {
+		result.Messages = []types.PromptMessage{}
+	}
+	for i, msg := range result.Messages {
+		if msg.Role != types.RoleUser && msg.Role != types.RoleAssistant {
+			return nil, errors.Errorf("prompt %s: message %d has invalid role %q", name, i, msg.Role)
+		}
+		if msg.Content == nil {
+			return nil, errors.Errorf("prompt %s: message %d has no content", name, i)
+		}
+	}
+	return result, nil
 }
 
-// ListResources handles the list resources request
+// ListResources returns every resource
 func (s *Server) ListResources(ctx context.Context) ([]types.Resource, error) {
-	if s.listResourcesHandler == nil {
-		return nil, errors.New("list resources handler not registered")
+	return collect(ctx, s, s.resourceSources())
+}
+
+// ListResourcesPage returns the page of resources starting at cursor and
+// the cursor of the next page, which is empty on the last page
+func (s *Server) ListResourcesPage(ctx context.Context, cursor string) ([]types.Resource, string, error) {
+	return paginate(ctx, s, s.resourceSources(), cursor)
+}
+
+// resourceSources returns the sources of the resources list
+func (s *Server) resourceSources() listSources[types.Resource] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return listSources[types.Resource]{
+		name:     "resources",
+		registry: s.resources.list,
+		all:      s.listResourcesHandler,
+		page:     s.listResourcesPageHandler,
 	}
-	return s.listResourcesHandler(ctx)
 }
 
-// ReadResource handles the read resource request
+// ReadResource handles the read resource request. Resources and templates
+// added with AddResource and AddResourceTemplate take precedence over the
+// OnReadResource handler.
 func (s *Server) ReadResource(ctx context.Context, uri string) ([]byte, string, error) {
-	if s.readResourceHandler == nil {
+	if read, ok := s.resources.match(uri); ok {
+		return read(ctx)
+	}
+
+	s.mu.RLock()
+	handler := s.readResourceHandler
+	s.mu.RUnlock()
+
+	if handler == nil {
+		if s.resources.len() > 0 {
+			return nil, "", unknownResourceError(uri)
+		}
 		return nil, "", errors.New("read resource handler not registered")
 	}
-	return s.readResourceHandler(ctx, uri)
+	return handler(ctx, uri)
 }
 
-// ListResourceTemplates handles the list resource templates request
+// ListResourceTemplates returns every resource template
 func (s *Server) ListResourceTemplates(ctx context.Context) ([]types.ResourceTemplate, error) {
-	if s.listResourceTemplatesHandler == nil {
-		return nil, errors.New("list resource templates handler not registered")
+	return collect(ctx, s, s.resourceTemplateSources())
+}
+
+// ListResourceTemplatesPage returns the page of resource templates starting
+// at cursor and the cursor of the next page, which is empty on the last page
+func (s *Server) ListResourceTemplatesPage(ctx context.Context, cursor string) ([]types.ResourceTemplate, string, error) {
+	return paginate(ctx, s, s.resourceTemplateSources(), cursor)
+}
+
+// resourceTemplateSources returns the sources of the resource templates
+// list
+func (s *Server) resourceTemplateSources() listSources[types.ResourceTemplate] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return listSources[types.ResourceTemplate]{
+		name:     "resource templates",
+		registry: s.resources.listTemplates,
+		all:      s.listResourceTemplatesHandler,
+		page:     s.listResourceTemplatesPageHandler,
 	}
-	return s.listResourceTemplatesHandler(ctx)
 }
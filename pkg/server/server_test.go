@@ -96,6 +96,44 @@ func TestServer_CallTool(t *testing.T) {
 	assert.Equal(t, expectedResult, result)
 }
 
+func TestServer_GetPrompt(t *testing.T) {
+	srv, err := New(&Options{Logger: types.NewNoOpLogger()})
+	assert.NoError(t, err)
+
+	// Test without handler
+	_, err = srv.GetPrompt(context.Background(), "review", nil)
+	assert.Error(t, err)
+
+	srv.OnGetPrompt(func(ctx context.Context, name string, args map[string]interface{}) (*types.GetPromptResult, error) {
+		switch name {
+		case "review":
+			return &types.GetPromptResult{
+				Description: "Review code",
+				Messages: []types.PromptMessage{
+					types.NewPromptMessage(types.RoleUser, types.NewTextContent("Review "+args["file"].(string))),
+				},
+			}, nil
+		case "empty":
+			return nil, nil
+		default:
+			return &types.GetPromptResult{Messages: []types.PromptMessage{{Role: "system", Content: types.NewTextContent("x")}}}, nil
+		}
+	})
+
+	result, err := srv.GetPrompt(context.Background(), "review", map[string]interface{}{"file": "main.go"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Review code", result.Description)
+	assert.Equal(t, []types.PromptMessage{{Role: types.RoleUser, Content: types.NewTextContent("Review main.go")}}, result.Messages)
+
+	// Results always carry a messages array
+	result, err = srv.GetPrompt(context.Background(), "empty", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []types.PromptMessage{}, result.Messages)
+
+	_, err = srv.GetPrompt(context.Background(), "invalid", nil)
+	assert.ErrorContains(t, err, "invalid role")
+}
+
 func TestServer_GetSession(t *testing.T) {
 	logger := types.NewNoOpLogger()
 	srv, err := New(&Options{
@@ -106,8 +144,8 @@ func TestServer_GetSession(t *testing.T) {
 	assert.NoError(t, err)
 
 	// Test non-existent session
-	session, err := srv.getSession("non-existent")
-	assert.Error(t, err)
+	session, ok := srv.Session("non-existent")
+	assert.False(t, ok)
 	assert.Nil(t, session)
 
 	// Test existing session
@@ -124,17 +162,97 @@ func TestServer_GetSession(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, resp)
 
-	// Get session from map
-	sessions := make([]string, 0)
-	srv.mu.RLock()
-	for id := range srv.sessions {
-		sessions = append(sessions, id)
-	}
-	srv.mu.RUnlock()
-
+	sessions := srv.Sessions()
 	assert.Len(t, sessions, 1)
-	session, err = srv.getSession(sessions[0])
-	assert.NoError(t, err)
+	session, ok = srv.Session(sessions[0].ID())
+	assert.True(t, ok)
 	assert.NotNil(t, session)
 	assert.Equal(t, "test-client", session.ClientInfo().Name)
+
+	// Closed sessions are gone
+	assert.NoError(t, srv.CloseSession(session.ID()))
+	assert.True(t, session.IsClosed())
+	assert.Empty(t, srv.Sessions())
+	assert.Error(t, srv.CloseSession(session.ID()))
+}
+
+func TestServer_ProtocolVersionNegotiation(t *testing.T) {
+	tests := []struct {
+		name      string
+		supported []string
+		requested string
+		want      string
+	}{
+		{name: "supported version is kept", requested: types.ProtocolVersion20250326, want: types.ProtocolVersion20250326},
+		{name: "unknown version gets latest", requested: "1.0", want: types.LatestProtocolVersion},
+		{
+			name:      "custom supported list",
+			supported: []string{types.ProtocolVersion20241105, types.ProtocolVersion20250326},
+			requested: types.ProtocolVersion20250618,
+			want:      types.ProtocolVersion20250326,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, err := New(&Options{
+				Name:             "test-server",
+				Version:          "1.0.0",
+				Logger:           types.NewNoOpLogger(),
+				ProtocolVersions: tt.supported,
+			})
+			assert.NoError(t, err)
+
+			resp, err := srv.Initialize(context.Background(), &types.InitializeRequest{ProtocolVersion: tt.requested})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, resp.ProtocolVersion)
+
+			for _, session := range srv.Sessions() {
+				assert.Equal(t, tt.want, session.ProtocolVersion())
+				assert.Equal(t, tt.want >= types.ProtocolVersion20250618, session.Supports(types.FeatureElicitation))
+				assert.Equal(t, tt.want >= types.ProtocolVersion20250326, session.Supports(types.FeatureToolAnnotations))
+			}
+		})
+	}
+}
+
+func TestServer_Capabilities(t *testing.T) {
+	srv, err := New(&Options{Logger: types.NewNoOpLogger()})
+	assert.NoError(t, err)
+
+	// Only logging is advertised without handlers
+	assert.Equal(t, types.ServerCapabilities{Logging: &types.LoggingCapability{}}, srv.Capabilities())
+
+	srv.OnListTools(func(ctx context.Context) ([]types.Tool, error) {
+		return nil, nil
+	})
+	srv.OnReadResource(func(ctx context.Context, uri string) ([]byte, string, error) {
+		return nil, "", nil
+	})
+
+	resp, err := srv.Initialize(context.Background(), &types.InitializeRequest{ProtocolVersion: types.LatestProtocolVersion})
+	assert.NoError(t, err)
+	assert.Equal(t, types.ServerCapabilities{
+		Tools:     &types.ToolsCapability{},
+		Resources: &types.ResourcesCapability{Subscribe: true},
+		Logging:   &types.LoggingCapability{},
+	}, resp.Capabilities)
+
+	// The hook amends the derived capabilities
+	srv, err = New(&Options{
+		Logger: types.NewNoOpLogger(),
+		Capabilities: func(caps types.ServerCapabilities) types.ServerCapabilities {
+			caps.Experimental = map[string]interface{}{"feature": true}
+			return caps
+		},
+	})
+	assert.NoError(t, err)
+	srv.OnGetPrompt(func(ctx context.Context, name string, args map[string]interface{}) (*types.GetPromptResult, error) {
+		return nil, nil
+	})
+	assert.Equal(t, types.ServerCapabilities{
+		Prompts:      &types.PromptsCapability{},
+		Logging:      &types.LoggingCapability{},
+		Experimental: map[string]interface{}{"feature": true},
+	}, srv.Capabilities())
 }
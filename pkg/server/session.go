@@ -1,32 +1,79 @@
 package server
 
 import (
+	"context"
 	"sync"
 	"time"
 
+	"github.com/pkg/errors"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
 	"github.com/harriteja/mcp-go-sdk/pkg/types"
 )
 
+// InitializationState represents the current state of session initialization
+type InitializationState int
+
+const (
+	// NotInitialized means the client has not sent initialize yet
+	NotInitialized InitializationState = iota
+	// Initializing means initialize succeeded but the client has not sent
+	// notifications/initialized yet
+	Initializing
+	// Initialized means the handshake is complete
+	Initialized
+)
+
 // Session represents a client session
 type Session struct {
 	mu sync.RWMutex
 
-	id        string
-	createdAt time.Time
-	expiresAt time.Time
+	id         string
+	createdAt  time.Time
+	expiresAt  time.Time
+	lastActive time.Time
+	state      InitializationState
+
+	done      chan struct{}
+	closeOnce sync.Once
+
+	notifier Notifier
+
+	// pending holds the channels of the requests sent to the client that
+	// await a response, keyed by request ID
+	pendingMu      sync.Mutex
+	pending        map[string]chan *jsonrpc.Response
+	nextRequestID  int64
+	requestTimeout time.Duration
+
+	// roots caches the roots of the client until it says they changed.
+	// rootsVersion counts the changes, so roots listed while one arrives are
+	// not cached.
+	rootsMu      sync.Mutex
+	roots        []types.Root
+	rootsCached  bool
+	rootsVersion uint64
 
 	clientInfo         types.Implementation
 	clientCapabilities types.ClientCapabilities
 	protocolVersion    string
+
+	// logLevel is the minimum level of the log messages sent to the client
+	logLevel types.LoggingLevel
 }
 
-// NewSession creates a new session
+// NewSession creates a new session for a client that sent initialize
 func NewSession(id string, req *types.InitializeRequest) *Session {
 	now := time.Now()
 	return &Session{
-		id:        id,
-		createdAt: now,
-		expiresAt: now.Add(24 * time.Hour), // Default 24h expiry
+		id:         id,
+		createdAt:  now,
+		expiresAt:  now.Add(24 * time.Hour), // Default 24h expiry
+		lastActive: now,
+		state:      Initializing,
+		done:       make(chan struct{}),
+
+		requestTimeout: DefaultClientRequestTimeout,
 
 		clientInfo:         req.ClientInfo,
 		clientCapabilities: req.Capabilities,
@@ -46,9 +93,80 @@ func (s *Session) CreatedAt() time.Time {
 
 // ExpiresAt returns when the session expires
 func (s *Session) ExpiresAt() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	return s.expiresAt
 }
 
+// LastActive returns when the session last received a message
+func (s *Session) LastActive() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lastActive
+}
+
+// touch records activity on the session
+func (s *Session) touch() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastActive = time.Now()
+}
+
+// Done returns a channel that is closed when the session is closed
+func (s *Session) Done() <-chan struct{} {
+	return s.done
+}
+
+// IsClosed reports whether the session has been closed
+func (s *Session) IsClosed() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// close closes the session. It reports false when it was already closed.
+func (s *Session) close() bool {
+	closed := false
+	s.closeOnce.Do(func() {
+		close(s.done)
+		closed = true
+	})
+	return closed
+}
+
+// setNotifier sets how notifications reach the client of the session
+func (s *Session) setNotifier(notifier Notifier) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.notifier = notifier
+}
+
+// Notify sends a notification to the client of the session, through the
+// transport the session is served on. It returns ErrNoNotifier when the
+// transport cannot deliver notifications to the client at the moment, e.g.
+// an HTTP client without an open event stream.
+func (s *Session) Notify(ctx context.Context, method string, params interface{}) error {
+	s.mu.RLock()
+	notifier := s.notifier
+	s.mu.RUnlock()
+
+	if notifier == nil {
+		return ErrNoNotifier
+	}
+	if s.IsClosed() {
+		return ErrSessionClosed
+	}
+
+	notification, err := jsonrpc.NewNotification(method, params)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode notification")
+	}
+	return notifier.Notify(ctx, notification)
+}
+
 // ClientInfo returns the client implementation info
 func (s *Session) ClientInfo() types.Implementation {
 	return s.clientInfo
@@ -59,14 +177,83 @@ func (s *Session) ClientCapabilities() types.ClientCapabilities {
 	return s.clientCapabilities
 }
 
-// ProtocolVersion returns the protocol version
+// ProtocolVersion returns the negotiated protocol version
 func (s *Session) ProtocolVersion() string {
 	return s.protocolVersion
 }
 
+// Supports reports whether the negotiated protocol version includes the
+// feature, so handlers can avoid sending what the client cannot understand
+func (s *Session) Supports(feature types.Feature) bool {
+	return types.SupportsFeature(s.protocolVersion, feature)
+}
+
+// State returns the initialization state of the session
+func (s *Session) State() InitializationState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.state
+}
+
+// IsInitialized reports whether the client completed the handshake with
+// notifications/initialized. The server must not send requests before.
+func (s *Session) IsInitialized() bool {
+	return s.State() == Initialized
+}
+
+// markInitialized records that the client sent notifications/initialized
+func (s *Session) markInitialized() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state = Initialized
+}
+
+// CheckClientCapability checks if the client supports a specific capability
+func (s *Session) CheckClientCapability(capability types.ClientCapabilities) bool {
+	clientCaps := s.clientCapabilities
+
+	// Check roots capability
+	if capability.Roots != nil {
+		if clientCaps.Roots == nil {
+			return false
+		}
+		if capability.Roots.ListChanged && !clientCaps.Roots.ListChanged {
+			return false
+		}
+	}
+
+	// Check sampling capability
+	if capability.Sampling != nil {
+		if clientCaps.Sampling == nil {
+			return false
+		}
+	}
+
+	// Check elicitation capability
+	if capability.Elicitation != nil {
+		if clientCaps.Elicitation == nil {
+			return false
+		}
+	}
+
+	// Check experimental capabilities
+	if capability.Experimental != nil {
+		if clientCaps.Experimental == nil {
+			return false
+		}
+		for expKey, expValue := range capability.Experimental {
+			if clientValue, ok := clientCaps.Experimental[expKey]; !ok || clientValue != expValue {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
 // IsExpired checks if the session has expired
 func (s *Session) IsExpired() bool {
-	return time.Now().After(s.expiresAt)
+	return time.Now().After(s.ExpiresAt())
 }
 
 // Extend extends the session expiry time
@@ -75,3 +262,12 @@ func (s *Session) Extend(duration time.Duration) {
 	defer s.mu.Unlock()
 	s.expiresAt = time.Now().Add(duration)
 }
+
+// extendUntil pushes the expiry time back to t, unless it is already later
+func (s *Session) extendUntil(t time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t.After(s.expiresAt) {
+		s.expiresAt = t
+	}
+}
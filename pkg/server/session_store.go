@@ -0,0 +1,283 @@
+package server
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// CloseReason tells why a session left its store
+type CloseReason string
+
+const (
+	// CloseReasonClosed means the session was closed explicitly
+	CloseReasonClosed CloseReason = "closed"
+	// CloseReasonExpired means the session outlived its TTL
+	CloseReasonExpired CloseReason = "expired"
+	// CloseReasonIdle means the session saw no activity for too long
+	CloseReasonIdle CloseReason = "idle"
+	// CloseReasonEvicted means the session made room for a newer one
+	CloseReasonEvicted CloseReason = "evicted"
+)
+
+// SessionCloseHook is called whenever a session leaves a SessionStore
+type SessionCloseHook func(session *Session, reason CloseReason)
+
+// SessionStore keeps track of the sessions of a server. Implementations
+// decide when sessions end and must call the registered close hooks whenever
+// a session leaves the store, whatever the reason.
+type SessionStore interface {
+	// Add stores a new session
+	Add(session *Session) error
+	// Get returns the session with the given ID, unless it has ended
+	Get(id string) (*Session, bool)
+	// Remove closes the session with the given ID. It reports whether the
+	// session was found.
+	Remove(id string) bool
+	// List returns the sessions in the store, oldest first
+	List() []*Session
+	// OnClose registers a hook called when a session leaves the store
+	OnClose(hook SessionCloseHook)
+}
+
+// MemorySessionStoreOptions configures a MemorySessionStore
+type MemorySessionStoreOptions struct {
+	// TTL is how long a session lives after it was created or last received
+	// a message, so active sessions do not expire. Defaults to 24 hours.
+	TTL time.Duration
+	// IdleTimeout ends sessions that saw no message for that long. Zero
+	// disables it.
+	IdleTimeout time.Duration
+	// MaxSessions limits the number of sessions. When full, the least
+	// recently active session is evicted. Zero means no limit.
+	MaxSessions int
+	// SweepInterval is how often ended sessions are swept. Defaults to one
+	// minute.
+	SweepInterval time.Duration
+}
+
+// MemorySessionStore is an in-memory SessionStore
+type MemorySessionStore struct {
+	mu       sync.Mutex
+	opts     MemorySessionStoreOptions
+	sessions map[string]*Session
+	hooks    []SessionCloseHook
+	now      func() time.Time
+
+	sweeping bool
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewMemorySessionStore creates a new in-memory session store. The sweeper
+// starts with the first session; Close stops it.
+func NewMemorySessionStore(opts MemorySessionStoreOptions) *MemorySessionStore {
+	if opts.TTL <= 0 {
+		opts.TTL = 24 * time.Hour
+	}
+	if opts.SweepInterval <= 0 {
+		opts.SweepInterval = time.Minute
+	}
+
+	return &MemorySessionStore{
+		opts:     opts,
+		sessions: make(map[string]*Session),
+		now:      time.Now,
+		stop:     make(chan struct{}),
+	}
+}
+
+// closedSession is a session that left the store, whose hooks still have to
+// run
+type closedSession struct {
+	session *Session
+	reason  CloseReason
+}
+
+// Add implements SessionStore
+func (m *MemorySessionStore) Add(session *Session) error {
+	m.mu.Lock()
+	if _, ok := m.sessions[session.ID()]; ok {
+		m.mu.Unlock()
+		return errors.New("session already exists: " + session.ID())
+	}
+
+	var closed []closedSession
+	if m.opts.MaxSessions > 0 && len(m.sessions) >= m.opts.MaxSessions {
+		closed = m.sweepLocked()
+		if len(m.sessions) >= m.opts.MaxSessions {
+			closed = append(closed, m.evictLocked())
+		}
+	}
+
+	session.Extend(m.opts.TTL)
+	m.sessions[session.ID()] = session
+	if !m.sweeping {
+		m.sweeping = true
+		go m.sweepLoop()
+	}
+	m.mu.Unlock()
+
+	m.runHooks(closed)
+	return nil
+}
+
+// Get implements SessionStore
+func (m *MemorySessionStore) Get(id string) (*Session, bool) {
+	m.mu.Lock()
+	session, ok := m.sessions[id]
+	if !ok {
+		m.mu.Unlock()
+		return nil, false
+	}
+
+	reason, ended := m.endedLocked(session)
+	if ended {
+		delete(m.sessions, id)
+	}
+	m.mu.Unlock()
+
+	if ended {
+		m.runHooks([]closedSession{{session: session, reason: reason}})
+		return nil, false
+	}
+	return session, true
+}
+
+// Remove implements SessionStore
+func (m *MemorySessionStore) Remove(id string) bool {
+	m.mu.Lock()
+	session, ok := m.sessions[id]
+	delete(m.sessions, id)
+	m.mu.Unlock()
+
+	if ok {
+		m.runHooks([]closedSession{{session: session, reason: CloseReasonClosed}})
+	}
+	return ok
+}
+
+// List implements SessionStore
+func (m *MemorySessionStore) List() []*Session {
+	m.Sweep()
+
+	m.mu.Lock()
+	sessions := make([]*Session, 0, len(m.sessions))
+	for _, session := range m.sessions {
+		sessions = append(sessions, session)
+	}
+	m.mu.Unlock()
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt().Before(sessions[j].CreatedAt())
+	})
+	return sessions
+}
+
+// OnClose implements SessionStore
+func (m *MemorySessionStore) OnClose(hook SessionCloseHook) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, hook)
+}
+
+// Sweep removes every expired or idle session and returns how many were
+// removed
+func (m *MemorySessionStore) Sweep() int {
+	m.mu.Lock()
+	closed := m.sweepLocked()
+	m.mu.Unlock()
+
+	m.runHooks(closed)
+	return len(closed)
+}
+
+// Close stops the sweeper and closes every session
+func (m *MemorySessionStore) Close() error {
+	m.stopOnce.Do(func() { close(m.stop) })
+
+	m.mu.Lock()
+	closed := make([]closedSession, 0, len(m.sessions))
+	for id, session := range m.sessions {
+		closed = append(closed, closedSession{session: session, reason: CloseReasonClosed})
+		delete(m.sessions, id)
+	}
+	m.mu.Unlock()
+
+	m.runHooks(closed)
+	return nil
+}
+
+// sweepLoop sweeps periodically until the store is closed
+func (m *MemorySessionStore) sweepLoop() {
+	ticker := time.NewTicker(m.opts.SweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.Sweep()
+		case <-m.stop:
+			return
+		}
+	}
+}
+
+// sweepLocked removes ended sessions. m.mu must be held.
+func (m *MemorySessionStore) sweepLocked() []closedSession {
+	var closed []closedSession
+	for id, session := range m.sessions {
+		if reason, ended := m.endedLocked(session); ended {
+			closed = append(closed, closedSession{session: session, reason: reason})
+			delete(m.sessions, id)
+		}
+	}
+	return closed
+}
+
+// evictLocked removes the least recently active session. m.mu must be held
+// and the store must not be empty.
+func (m *MemorySessionStore) evictLocked() closedSession {
+	var oldest *Session
+	for _, session := range m.sessions {
+		if oldest == nil || session.LastActive().Before(oldest.LastActive()) {
+			oldest = session
+		}
+	}
+	delete(m.sessions, oldest.ID())
+	return closedSession{session: oldest, reason: CloseReasonEvicted}
+}
+
+// endedLocked reports whether the session has expired or has been idle for
+// too long. The expiry of sessions that saw activity is pushed back first.
+func (m *MemorySessionStore) endedLocked(session *Session) (CloseReason, bool) {
+	now := m.now()
+	session.extendUntil(session.LastActive().Add(m.opts.TTL))
+	if now.After(session.ExpiresAt()) {
+		return CloseReasonExpired, true
+	}
+	if m.opts.IdleTimeout > 0 && now.Sub(session.LastActive()) > m.opts.IdleTimeout {
+		return CloseReasonIdle, true
+	}
+	return "", false
+}
+
+// runHooks runs the close hooks for sessions that left the store. It must be
+// called without holding m.mu so hooks may use the store.
+func (m *MemorySessionStore) runHooks(closed []closedSession) {
+	if len(closed) == 0 {
+		return
+	}
+
+	m.mu.Lock()
+	hooks := append([]SessionCloseHook(nil), m.hooks...)
+	m.mu.Unlock()
+
+	for _, c := range closed {
+		for _, hook := range hooks {
+			hook(c.session, c.reason)
+		}
+	}
+}
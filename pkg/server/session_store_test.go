@@ -0,0 +1,128 @@
+package server
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+)
+
+func newTestStore(opts MemorySessionStoreOptions) (*MemorySessionStore, *time.Time, map[string]CloseReason) {
+	store := NewMemorySessionStore(opts)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	closed := make(map[string]CloseReason)
+	store.OnClose(func(session *Session, reason CloseReason) {
+		closed[session.ID()] = reason
+	})
+	return store, &now, closed
+}
+
+func addTestSession(t *testing.T, store *MemorySessionStore, id string) *Session {
+	session := NewSession(id, &types.InitializeRequest{})
+	require.NoError(t, store.Add(session))
+	return session
+}
+
+func TestMemorySessionStore_Expiry(t *testing.T) {
+	store, now, closed := newTestStore(MemorySessionStoreOptions{
+		TTL:         time.Hour,
+		IdleTimeout: 10 * time.Minute,
+	})
+	defer store.Close()
+
+	addTestSession(t, store, "old")
+	idle := addTestSession(t, store, "idle")
+	active := addTestSession(t, store, "active")
+
+	idle.mu.Lock()
+	idle.lastActive = now.Add(-11 * time.Minute)
+	idle.mu.Unlock()
+	old := store.sessions["old"]
+	old.mu.Lock()
+	old.expiresAt = now.Add(-time.Second)
+	old.lastActive = now.Add(-time.Hour - time.Second)
+	old.mu.Unlock()
+
+	_, ok := store.Get("old")
+	assert.False(t, ok)
+	assert.Equal(t, 1, store.Sweep())
+	assert.Equal(t, map[string]CloseReason{"old": CloseReasonExpired, "idle": CloseReasonIdle}, closed)
+
+	sessions := store.List()
+	require.Len(t, sessions, 1)
+	assert.Same(t, active, sessions[0])
+}
+
+func TestMemorySessionStore_ActiveSessionsDoNotExpire(t *testing.T) {
+	store, now, closed := newTestStore(MemorySessionStoreOptions{TTL: time.Hour})
+	defer store.Close()
+
+	active := addTestSession(t, store, "active")
+	addTestSession(t, store, "quiet")
+
+	// active keeps receiving messages well past its TTL
+	for i := 0; i < 5; i++ {
+		*now = now.Add(30 * time.Minute)
+		active.mu.Lock()
+		active.lastActive = *now
+		active.mu.Unlock()
+		store.Sweep()
+	}
+
+	got, ok := store.Get("active")
+	require.True(t, ok)
+	assert.Same(t, active, got)
+	assert.Equal(t, now.Add(time.Hour), active.ExpiresAt())
+	assert.Equal(t, map[string]CloseReason{"quiet": CloseReasonExpired}, closed)
+
+	// It expires once it stays quiet for the TTL
+	*now = now.Add(time.Hour + time.Second)
+	_, ok = store.Get("active")
+	assert.False(t, ok)
+	assert.Equal(t, CloseReasonExpired, closed["active"])
+}
+
+func TestMemorySessionStore_MaxSessions(t *testing.T) {
+	store, now, closed := newTestStore(MemorySessionStoreOptions{MaxSessions: 3})
+	defer store.Close()
+
+	sessions := make([]*Session, 3)
+	for i := range sessions {
+		sessions[i] = addTestSession(t, store, fmt.Sprintf("s%d", i))
+	}
+
+	// s0 is the least recently active once the others saw traffic
+	*now = now.Add(time.Second)
+	for i, session := range sessions {
+		session.mu.Lock()
+		session.lastActive = now.Add(time.Duration(i) * time.Millisecond)
+		session.mu.Unlock()
+	}
+
+	addTestSession(t, store, "s3")
+	assert.Equal(t, map[string]CloseReason{"s0": CloseReasonEvicted}, closed)
+	assert.Len(t, store.List(), 3)
+
+	assert.Error(t, store.Add(sessions[1]), "duplicate IDs are rejected")
+}
+
+func TestMemorySessionStore_RemoveAndClose(t *testing.T) {
+	store, _, closed := newTestStore(MemorySessionStoreOptions{})
+
+	addTestSession(t, store, "a")
+	addTestSession(t, store, "b")
+
+	assert.True(t, store.Remove("a"))
+	assert.False(t, store.Remove("a"))
+	assert.Equal(t, map[string]CloseReason{"a": CloseReasonClosed}, closed)
+
+	require.NoError(t, store.Close())
+	assert.Equal(t, CloseReasonClosed, closed["b"])
+	assert.Empty(t, store.List())
+}
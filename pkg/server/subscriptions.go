@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+)
+
+// subscriptions records which sessions subscribed to which resources
+type subscriptions struct {
+	mu sync.Mutex
+	// uris maps resource URIs to the subscribed sessions, keyed by ID
+	uris map[string]map[string]*Session
+}
+
+// add subscribes a session to a resource
+func (s *subscriptions) add(uri string, session *Session) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.uris == nil {
+		s.uris = make(map[string]map[string]*Session)
+	}
+	if s.uris[uri] == nil {
+		s.uris[uri] = make(map[string]*Session)
+	}
+	s.uris[uri][session.ID()] = session
+}
+
+// remove unsubscribes a session from a resource
+func (s *subscriptions) remove(uri, sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.uris[uri], sessionID)
+	if len(s.uris[uri]) == 0 {
+		delete(s.uris, uri)
+	}
+}
+
+// removeSession drops every subscription of a session
+func (s *subscriptions) removeSession(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for uri, sessions := range s.uris {
+		delete(sessions, sessionID)
+		if len(sessions) == 0 {
+			delete(s.uris, uri)
+		}
+	}
+}
+
+// sessions returns the sessions subscribed to a resource
+func (s *subscriptions) sessions(uri string) []*Session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sessions := make([]*Session, 0, len(s.uris[uri]))
+	for _, session := range s.uris[uri] {
+		sessions = append(sessions, session)
+	}
+	return sessions
+}
+
+// SubscribeResource subscribes the session of ctx to updates of a resource,
+// which NotifyResourceUpdated announces
+func (s *Server) SubscribeResource(ctx context.Context, uri string) error {
+	session, ok := SessionFromContext(ctx)
+	if !ok {
+		return errors.New("no session to subscribe")
+	}
+	if uri == "" {
+		return jsonrpc.NewError(jsonrpc.CodeInvalidParams, "missing resource URI")
+	}
+	s.subscriptions.add(uri, session)
+	return nil
+}
+
+// UnsubscribeResource cancels a subscription of the session of ctx
+func (s *Server) UnsubscribeResource(ctx context.Context, uri string) error {
+	session, ok := SessionFromContext(ctx)
+	if !ok {
+		return errors.New("no session to unsubscribe")
+	}
+	s.subscriptions.remove(uri, session.ID())
+	return nil
+}
+
+// NotifyResourceUpdated sends notifications/resources/updated to the
+// sessions subscribed to the resource. Failures are logged, like those of
+// other notifications sent to several sessions.
+func (s *Server) NotifyResourceUpdated(uri string) {
+	ctx := context.Background()
+	params := types.ResourceUpdatedNotification{URI: uri}
+	for _, session := range s.subscriptions.sessions(uri) {
+		if err := session.Notify(ctx, types.NotificationResourceUpdated, params); err != nil && !errors.Is(err, ErrNoNotifier) {
+			s.logger.Warn(ctx, "server", "notify", "Failed to send "+types.NotificationResourceUpdated+" to session "+session.ID()+": "+err.Error())
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+)
+
+func TestServer_ResourceSubscriptions(t *testing.T) {
+	srv, d := newTestDispatcher(t)
+
+	// connect returns a context bound to a new initialized session whose
+	// notifications are recorded
+	connect := func() (context.Context, *recordingNotifier) {
+		notifier := &recordingNotifier{}
+		conn := NewConnection()
+		conn.SetNotifier(notifier)
+		ctx := WithConnection(context.Background(), conn)
+		dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18"}}`)
+		dispatch(t, ctx, d, `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+		return ctx, notifier
+	}
+	ctxA, notifierA := connect()
+	ctxB, notifierB := connect()
+
+	resp := dispatch(t, ctxA, d, `{"jsonrpc":"2.0","id":2,"method":"resources/subscribe","params":{"uri":"file:///a"}}`)
+	require.Nil(t, resp.Error)
+	assert.JSONEq(t, `{}`, string(resp.Result))
+	resp = dispatch(t, ctxB, d, `{"jsonrpc":"2.0","id":2,"method":"resources/subscribe","params":{"uri":"file:///b"}}`)
+	require.Nil(t, resp.Error)
+
+	resp = dispatch(t, ctxA, d, `{"jsonrpc":"2.0","id":3,"method":"resources/subscribe","params":{}}`)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, jsonrpc.CodeInvalidParams, resp.Error.Code)
+
+	// Only subscribed sessions are notified
+	srv.NotifyResourceUpdated("file:///a")
+	require.Equal(t, []string{types.NotificationResourceUpdated}, notifierA.methods())
+	assert.JSONEq(t, `{"uri":"file:///a"}`, string(notifierA.notifications[0].Params))
+	assert.Empty(t, notifierB.methods())
+
+	resp = dispatch(t, ctxA, d, `{"jsonrpc":"2.0","id":4,"method":"resources/unsubscribe","params":{"uri":"file:///a"}}`)
+	require.Nil(t, resp.Error)
+	srv.NotifyResourceUpdated("file:///a")
+	assert.Len(t, notifierA.methods(), 1)
+
+	// Subscriptions end with their session
+	sessionB, ok := SessionFromContext(ctxB)
+	require.True(t, ok)
+	require.NoError(t, srv.CloseSession(sessionB.ID()))
+	assert.Empty(t, srv.subscriptions.sessions("file:///b"))
+}
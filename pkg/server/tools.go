@@ -0,0 +1,213 @@
+package server
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonschema"
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+)
+
+// ToolHandler handles calls to a single tool. The result is wrapped the same
+// way as the results of OnCallTool handlers.
+type ToolHandler func(ctx context.Context, args map[string]interface{}) (interface{}, error)
+
+// OutputValidation tells what the server does when a tool returns structured
+// content that does not match its output schema
+type OutputValidation int
+
+const (
+	// OutputValidationFail replaces the result with an internal error, so
+	// malformed output never reaches clients
+	OutputValidationFail OutputValidation = iota
+	// OutputValidationWarn logs a warning and sends the result anyway
+	OutputValidationWarn
+	// OutputValidationOff skips output validation
+	OutputValidationOff
+)
+
+// registeredTool is a tool added with AddTool
+type registeredTool struct {
+	tool    types.Tool
+	handler ToolHandler
+
+	// input and output validate the arguments and the structured content
+	// against the schemas of the tool, if any
+	input  *jsonschema.Validator
+	output *jsonschema.Validator
+}
+
+// newRegisteredTool compiles the schema of a tool
+func newRegisteredTool(tool types.Tool, handler ToolHandler) (*registeredTool, error) {
+	t := &registeredTool{tool: tool, handler: handler}
+	if len(tool.InputSchema) > 0 {
+		input, err := jsonschema.Compile(tool.InputSchema)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid input schema of tool "+tool.Name)
+		}
+		t.input = input
+	}
+	if len(tool.OutputSchema) > 0 {
+		output, err := jsonschema.Compile(tool.OutputSchema)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid output schema of tool "+tool.Name)
+		}
+		t.output = output
+	}
+	return t, nil
+}
+
+// call validates the arguments and invokes the handler. Invalid arguments
+// are rejected with an invalid params error listing the offending values.
+func (t *registeredTool) call(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	if t.input != nil {
+		value := args
+		if value == nil {
+			value = map[string]interface{}{}
+		}
+		result, err := t.input.Validate(value)
+		if err != nil {
+			return nil, err
+		}
+		if !result.Valid {
+			rpcErr := jsonrpc.NewError(jsonrpc.CodeInvalidParams, "invalid arguments for tool "+t.tool.Name+": "+jsonschema.FormatErrors(result))
+			rpcErr.Data = map[string]interface{}{"errors": result.Errors}
+			return nil, rpcErr
+		}
+	}
+	return t.handler(ctx, args)
+}
+
+// checkOutput validates the structured content of a successful result
+// against the output schema of the tool
+func (t *registeredTool) checkOutput(result *types.CallToolResult) error {
+	if t.output == nil || result.IsError {
+		return nil
+	}
+	if result.StructuredContent == nil {
+		return errors.New("tool " + t.tool.Name + " has an output schema but returned no structured content")
+	}
+
+	res, err := t.output.Validate(result.StructuredContent)
+	if err != nil {
+		return err
+	}
+	if !res.Valid {
+		rpcErr := jsonrpc.NewError(jsonrpc.CodeInternalError, "invalid structured content from tool "+t.tool.Name+": "+jsonschema.FormatErrors(res))
+		rpcErr.Data = map[string]interface{}{"errors": res.Errors}
+		return rpcErr
+	}
+	return nil
+}
+
+// toolRegistry holds the tools added with AddTool, in the order they were
+// added
+type toolRegistry struct {
+	mu    sync.RWMutex
+	tools map[string]*registeredTool
+	names []string
+}
+
+// add adds or replaces a tool
+func (r *toolRegistry) add(tool *registeredTool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	name := tool.tool.Name
+	if r.tools == nil {
+		r.tools = make(map[string]*registeredTool)
+	}
+	if _, ok := r.tools[name]; !ok {
+		r.names = append(r.names, name)
+	}
+	r.tools[name] = tool
+}
+
+// remove removes a tool and reports whether it was registered
+func (r *toolRegistry) remove(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.tools[name]; !ok {
+		return false
+	}
+	delete(r.tools, name)
+	for i, n := range r.names {
+		if n == name {
+			r.names = append(r.names[:i], r.names[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// get returns the tool with the given name
+func (r *toolRegistry) get(name string) (*registeredTool, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	tool, ok := r.tools[name]
+	return tool, ok
+}
+
+// list returns the registered tools
+func (r *toolRegistry) list() []types.Tool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tools := make([]types.Tool, 0, len(r.names))
+	for _, name := range r.names {
+		tools = append(tools, r.tools[name].tool)
+	}
+	return tools
+}
+
+// len returns the number of registered tools
+func (r *toolRegistry) len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.names)
+}
+
+// AddTool registers a tool and its handler. The tool is listed by tools/list
+// and calls to it are routed to the handler, ahead of any OnCallTool
+// handler. Arguments are validated against the input schema of the tool,
+// compiled once here, before the handler is called, and structured content
+// against the output schema as configured by Options.OutputValidation.
+// Adding a tool with the name of a registered one replaces it. Initialized
+// sessions are sent notifications/tools/list_changed.
+func (s *Server) AddTool(tool types.Tool, handler ToolHandler) error {
+	if tool.Name == "" {
+		return errors.New("tool name is required")
+	}
+	if handler == nil {
+		return errors.New("tool handler is required")
+	}
+
+	registered, err := newRegisteredTool(tool, handler)
+	if err != nil {
+		return err
+	}
+
+	s.tools.add(registered)
+	s.broadcast(context.Background(), types.NotificationToolsListChanged, nil)
+	return nil
+}
+
+// RemoveTool removes a tool registered with AddTool. It reports whether the
+// tool was registered; if so, initialized sessions are sent
+// notifications/tools/list_changed.
+func (s *Server) RemoveTool(name string) bool {
+	if !s.tools.remove(name) {
+		return false
+	}
+	s.broadcast(context.Background(), types.NotificationToolsListChanged, nil)
+	return true
+}
+
+// unknownToolError is returned for calls to a tool that does not exist
+func unknownToolError(name string) error {
+	return jsonrpc.NewError(jsonrpc.CodeInvalidParams, "unknown tool: "+name)
+}
@@ -0,0 +1,277 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+)
+
+// recordingNotifier records the notifications sent to a session
+type recordingNotifier struct {
+	mu            sync.Mutex
+	notifications []*jsonrpc.Notification
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, notification *jsonrpc.Notification) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.notifications = append(n.notifications, notification)
+	return nil
+}
+
+func (n *recordingNotifier) methods() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	methods := make([]string, 0, len(n.notifications))
+	for _, notification := range n.notifications {
+		methods = append(methods, notification.Method)
+	}
+	return methods
+}
+
+func TestServer_ToolRegistry(t *testing.T) {
+	srv, d := newTestDispatcher(t)
+
+	notifier := &recordingNotifier{}
+	conn := NewConnection()
+	conn.SetNotifier(notifier)
+	ctx := WithConnection(context.Background(), conn)
+	dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18"}}`)
+	dispatch(t, ctx, d, `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+
+	echo := func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return args["message"], nil
+	}
+	require.NoError(t, srv.AddTool(types.Tool{Name: "echo"}, echo))
+	require.NoError(t, srv.AddTool(types.Tool{Name: "upper"}, echo))
+	assert.Error(t, srv.AddTool(types.Tool{}, echo))
+	assert.Error(t, srv.AddTool(types.Tool{Name: "nil"}, nil))
+
+	assert.Equal(t, &types.ToolsCapability{ListChanged: true}, srv.Capabilities().Tools)
+
+	tools, err := srv.ListTools(ctx)
+	require.NoError(t, err)
+	require.Len(t, tools, 2)
+	assert.Equal(t, "echo", tools[0].Name)
+	assert.Equal(t, "upper", tools[1].Name)
+
+	resp := dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"echo","arguments":{"message":"hi"}}}`)
+	require.Nil(t, resp.Error)
+	assert.JSONEq(t, `{"content":[{"type":"text","text":"hi"}]}`, string(resp.Result))
+
+	resp = dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"missing"}}`)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, jsonrpc.CodeInvalidParams, resp.Error.Code)
+	assert.Equal(t, "unknown tool: missing", resp.Error.Message)
+
+	assert.True(t, srv.RemoveTool("upper"))
+	assert.False(t, srv.RemoveTool("upper"))
+	tools, err = srv.ListTools(ctx)
+	require.NoError(t, err)
+	assert.Len(t, tools, 1)
+
+	// Every change is announced once, failed additions and removals are not
+	assert.Equal(t, []string{
+		types.NotificationToolsListChanged,
+		types.NotificationToolsListChanged,
+		types.NotificationToolsListChanged,
+	}, notifier.methods())
+}
+
+func TestServer_ToolRegistryWithHandlers(t *testing.T) {
+	srv, err := New(&Options{Logger: types.NewNoOpLogger()})
+	require.NoError(t, err)
+
+	srv.OnListTools(func(ctx context.Context) ([]types.Tool, error) {
+		return []types.Tool{{Name: "legacy"}}, nil
+	})
+	srv.OnCallTool(func(ctx context.Context, name string, args map[string]interface{}) (interface{}, error) {
+		return "legacy " + name, nil
+	})
+	require.NoError(t, srv.AddTool(types.Tool{Name: "added"}, func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return "added", nil
+	}))
+
+	tools, err := srv.ListTools(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []types.Tool{{Name: "added"}, {Name: "legacy"}}, tools)
+
+	result, err := srv.CallTool(context.Background(), "added", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "added", result)
+
+	result, err = srv.CallTool(context.Background(), "other", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "legacy other", result)
+}
+
+func TestServer_ToolArgumentValidation(t *testing.T) {
+	srv, d := newTestDispatcher(t)
+
+	called := false
+	require.NoError(t, srv.AddTool(types.Tool{
+		Name:        "greet",
+		InputSchema: json.RawMessage(`{"type":"object","properties":{"name":{"type":"string","minLength":1}},"required":["name"]}`),
+	}, func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		called = true
+		return "hello " + args["name"].(string), nil
+	}))
+	assert.Error(t, srv.AddTool(types.Tool{Name: "broken", InputSchema: json.RawMessage(`{"type":7}`)}, func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return nil, nil
+	}))
+
+	ctx := initialize(t, d)
+	for _, args := range []string{`{}`, `{"name":""}`, `{"name":42}`} {
+		resp := dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"greet","arguments":`+args+`}}`)
+		require.NotNil(t, resp.Error, args)
+		assert.Equal(t, jsonrpc.CodeInvalidParams, resp.Error.Code)
+		assert.Contains(t, resp.Error.Message, "/name: ")
+
+		data, err := json.Marshal(resp.Error.Data)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"path":"/name"`)
+	}
+	assert.False(t, called, "handler must not run with invalid arguments")
+
+	resp := dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"greet","arguments":{"name":"Ada"}}}`)
+	require.Nil(t, resp.Error)
+	assert.True(t, called)
+}
+
+func TestServer_ToolOutputValidation(t *testing.T) {
+	schema := json.RawMessage(`{"type":"object","properties":{"n":{"type":"integer"}},"required":["n"]}`)
+	results := map[string]*types.CallToolResult{
+		"valid":   {Content: []types.Content{}, StructuredContent: map[string]interface{}{"n": 1}},
+		"invalid": {Content: []types.Content{}, StructuredContent: map[string]interface{}{"n": "one"}},
+		"missing": types.NewTextToolResult("no structure"),
+		"failed":  types.NewErrorToolResult("tool failed"),
+	}
+
+	tests := []struct {
+		mode    OutputValidation
+		wantErr map[string]bool
+	}{
+		{mode: OutputValidationFail, wantErr: map[string]bool{"invalid": true, "missing": true}},
+		{mode: OutputValidationWarn, wantErr: map[string]bool{}},
+		{mode: OutputValidationOff, wantErr: map[string]bool{}},
+	}
+
+	for _, tt := range tests {
+		srv, err := New(&Options{Logger: types.NewNoOpLogger(), OutputValidation: tt.mode})
+		require.NoError(t, err)
+		for name, result := range results {
+			require.NoError(t, srv.AddTool(types.Tool{Name: name, OutputSchema: schema}, func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+				return result, nil
+			}))
+		}
+
+		for name, result := range results {
+			got, err := srv.CallTool(context.Background(), name, nil)
+			if tt.wantErr[name] {
+				require.Error(t, err, "mode %d, tool %s", tt.mode, name)
+				assert.Equal(t, jsonrpc.CodeInternalError, jsonrpc.ErrorFrom(err).Code)
+				continue
+			}
+			require.NoError(t, err, "mode %d, tool %s", tt.mode, name)
+			assert.Equal(t, result, got)
+		}
+	}
+}
+
+func TestDispatcher_StructuredContentNeedsProtocolSupport(t *testing.T) {
+	srv, d := newTestDispatcher(t)
+	require.NoError(t, srv.AddTool(NewTypedTool("add", "", func(ctx context.Context, in addInput) (addOutput, error) {
+		return addOutput{Sum: in.A + in.B}, nil
+	})))
+
+	ctx := WithConnection(context.Background(), NewConnection())
+	dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}`)
+	dispatch(t, ctx, d, `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+
+	resp := dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`)
+	require.Nil(t, resp.Error)
+	assert.NotContains(t, string(resp.Result), "outputSchema")
+
+	resp = dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"add","arguments":{"a":1,"b":2}}}`)
+	require.Nil(t, resp.Error)
+	assert.JSONEq(t, `{"content":[{"type":"text","text":"{\"sum\":3}"}]}`, string(resp.Result))
+}
+
+func TestDispatcher_ToolAnnotationsNeedProtocolSupport(t *testing.T) {
+	srv, d := newTestDispatcher(t)
+	require.NoError(t, srv.AddTool(types.Tool{Name: "rm", Annotations: map[string]interface{}{"destructiveHint": true}}, func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return nil, nil
+	}))
+
+	for version, want := range map[string]string{
+		"2024-11-05": `{"tools":[{"name":"rm","description":""}]}`,
+		"2025-03-26": `{"tools":[{"name":"rm","description":"","annotations":{"destructiveHint":true}}]}`,
+	} {
+		ctx := WithConnection(context.Background(), NewConnection())
+		dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"`+version+`"}}`)
+		dispatch(t, ctx, d, `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+
+		resp := dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`)
+		require.Nil(t, resp.Error)
+		assert.JSONEq(t, want, string(resp.Result), version)
+	}
+}
+
+func TestDispatcher_ContentNeedsProtocolSupport(t *testing.T) {
+	srv, d := newTestDispatcher(t)
+	srv.OnCallTool(func(ctx context.Context, name string, args map[string]interface{}) (interface{}, error) {
+		return &types.CallToolResult{Content: []types.Content{
+			types.NewTextContent("files"),
+			types.NewResourceLink("file:///a.txt", "a", "text/plain"),
+			types.NewAudioContent([]byte("wav"), "audio/wav"),
+		}}, nil
+	})
+	srv.OnGetPrompt(func(ctx context.Context, name string, args map[string]interface{}) (*types.GetPromptResult, error) {
+		return &types.GetPromptResult{Messages: []types.PromptMessage{
+			types.NewPromptMessage(types.RoleUser, types.NewResourceLink("file:///a.txt", "a", "text/plain")),
+		}}, nil
+	})
+
+	// 2025-03-26 has audio content but no resource links
+	ctx := WithConnection(context.Background(), NewConnection())
+	dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}`)
+	dispatch(t, ctx, d, `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+
+	resp := dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"files"}}`)
+	require.Nil(t, resp.Error)
+	assert.JSONEq(t, `{"content":[
+		{"type":"text","text":"files"},
+		{"type":"text","text":"Resource a: file:///a.txt"},
+		{"type":"audio","data":"d2F2","mimeType":"audio/wav"}
+	]}`, string(resp.Result))
+
+	resp = dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":3,"method":"prompts/get","params":{"name":"files"}}`)
+	require.Nil(t, resp.Error)
+	assert.JSONEq(t, `{"messages":[{"role":"user","content":{"type":"text","text":"Resource a: file:///a.txt"}}]}`, string(resp.Result))
+
+	// 2024-11-05 has neither
+	ctx = WithConnection(context.Background(), NewConnection())
+	dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05"}}`)
+	dispatch(t, ctx, d, `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+
+	resp = dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"files"}}`)
+	require.Nil(t, resp.Error)
+	assert.JSONEq(t, `{"content":[
+		{"type":"text","text":"files"},
+		{"type":"text","text":"Resource a: file:///a.txt"},
+		{"type":"text","text":"[audio/wav audio not supported by this protocol version]"}
+	]}`, string(resp.Result))
+
+	// The latest version receives the blocks as they are
+	resp = dispatch(t, initialize(t, d), d, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"files"}}`)
+	require.Nil(t, resp.Error)
+	assert.Contains(t, string(resp.Result), `"type":"resource_link"`)
+}
@@ -0,0 +1,208 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
+	"github.com/harriteja/mcp-go-sdk/pkg/server"
+	transporterrors "github.com/harriteja/mcp-go-sdk/pkg/server/transport/errors"
+)
+
+// eventStream writes messages to the client of a session as server-sent
+// events, over the response to a GET request
+type eventStream struct {
+	mu      sync.Mutex
+	w       http.ResponseWriter
+	flusher http.Flusher
+	// closed is set once the request has returned, after which the
+	// response must not be written anymore
+	closed bool
+}
+
+// send writes one message as a single event
+func (s *eventStream) send(data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return server.ErrNoNotifier
+	}
+	if _, err := fmt.Fprintf(s.w, "event: message\ndata: %s\n\n", data); err != nil {
+		return errors.Wrap(err, "failed to write event")
+	}
+	s.flusher.Flush()
+	return nil
+}
+
+// httpNotifier delivers the notifications of the session bound to a
+// connection over the event stream the client opened for it
+type httpNotifier struct {
+	transport *HTTPTransport
+	conn      *server.Connection
+}
+
+// Notify implements server.Notifier. Clients that have no event stream open
+// cannot be reached, which is reported as server.ErrNoNotifier.
+func (n *httpNotifier) Notify(ctx context.Context, notification *jsonrpc.Notification) error {
+	return n.send(notification)
+}
+
+// SendRequest implements server.RequestSender. The client posts the
+// response.
+func (n *httpNotifier) SendRequest(ctx context.Context, request *jsonrpc.Request) error {
+	return n.send(request)
+}
+
+// send writes a message on the event stream of the session
+func (n *httpNotifier) send(msg interface{}) error {
+	session := n.conn.Session()
+	if session == nil {
+		return server.ErrNoNotifier
+	}
+	stream := n.transport.stream(session.ID())
+	if stream == nil {
+		return server.ErrNoNotifier
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode message")
+	}
+	return stream.send(data)
+}
+
+// stream returns the event stream open for a session, if any
+func (t *HTTPTransport) stream(sessionID string) *eventStream {
+	t.streamsMu.Lock()
+	defer t.streamsMu.Unlock()
+	return t.streams[sessionID]
+}
+
+// handleEventStream opens the event stream on which the server sends
+// notifications to the client of a session. A session has at most one
+// stream, which stays open until the client disconnects or the session
+// ends.
+func (t *HTTPTransport) handleEventStream(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.Header.Get(transporterrors.HeaderSessionID)
+	if sessionID == "" {
+		http.Error(w, "Missing session ID", http.StatusBadRequest)
+		return
+	}
+	session, ok := t.server.Session(sessionID)
+	if !ok {
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	stream := &eventStream{w: w, flusher: flusher}
+	t.streamsMu.Lock()
+	if _, open := t.streams[sessionID]; open {
+		t.streamsMu.Unlock()
+		http.Error(w, "Event stream already open", http.StatusConflict)
+		return
+	}
+	t.streams[sessionID] = stream
+	t.streamsMu.Unlock()
+
+	defer func() {
+		t.streamsMu.Lock()
+		delete(t.streams, sessionID)
+		t.streamsMu.Unlock()
+
+		stream.mu.Lock()
+		stream.closed = true
+		stream.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set(transporterrors.HeaderSessionID, sessionID)
+	stream.mu.Lock()
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	stream.mu.Unlock()
+
+	select {
+	case <-r.Context().Done():
+	case <-session.Done():
+	}
+}
+
+// responseStream sends the notifications and requests of a request, such as
+// its progress, as server-sent events on the response to the POST carrying
+// it.
+// The stream starts with the first notification, so requests that send none
+// are answered with plain JSON.
+type responseStream struct {
+	mu     sync.Mutex
+	w      http.ResponseWriter
+	conn   *server.Connection
+	events *eventStream
+	closed bool
+}
+
+// Notify implements server.Notifier
+func (s *responseStream) Notify(ctx context.Context, notification *jsonrpc.Notification) error {
+	return s.send(notification)
+}
+
+// SendRequest implements server.RequestSender. The client posts the
+// response while the stream stays open.
+func (s *responseStream) SendRequest(ctx context.Context, request *jsonrpc.Request) error {
+	return s.send(request)
+}
+
+// send writes a message on the stream, starting it if needed
+func (s *responseStream) send(msg interface{}) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode message")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return server.ErrNoNotifier
+	}
+	if s.events == nil {
+		flusher, ok := s.w.(http.Flusher)
+		if !ok {
+			return server.ErrNoNotifier
+		}
+		if session := s.conn.Session(); session != nil {
+			s.w.Header().Set(transporterrors.HeaderSessionID, session.ID())
+		}
+		s.w.Header().Set("Content-Type", "text/event-stream")
+		s.w.Header().Set("Cache-Control", "no-cache")
+		s.w.WriteHeader(http.StatusOK)
+		s.events = &eventStream{w: s.w, flusher: flusher}
+	}
+	return s.events.send(data)
+}
+
+// finish stops the stream from taking notifications and returns it if it
+// started, in which case the response must be sent on it
+func (s *responseStream) finish() *eventStream {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return s.events
+}
+
+// acceptsEvents reports whether the client accepts server-sent events
+func acceptsEvents(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
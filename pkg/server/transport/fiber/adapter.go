@@ -21,7 +21,9 @@ func New(srv *server.Server, logger types.Logger) *Adapter {
 	}
 }
 
-// Handler returns a Fiber handler function
+// Handler returns a Fiber handler function. It buffers the whole response,
+// so it serves POST requests; RegisterRoutes also routes GET requests, which
+// open event streams.
 func (a *Adapter) Handler() fiber.Handler {
 	return adaptor.HTTPHandler(a.transport.Handler())
 }
@@ -33,6 +35,7 @@ func (a *Adapter) RegisterRoutes(app *fiber.App) {
 		return c.SendString("OK")
 	})
 
-	// MCP endpoint
+	// MCP endpoint, where GET opens the event stream of a session
 	app.Post("/mcp", a.Handler())
+	app.Get("/mcp", a.handleGet)
 }
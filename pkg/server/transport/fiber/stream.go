@@ -0,0 +1,178 @@
+package fiber
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+)
+
+// streamKeepAlive is how often an idle event stream gets a comment. fasthttp
+// only notices a disconnected client when writing to it.
+var streamKeepAlive = 15 * time.Second
+
+// streamWriter is the http.ResponseWriter of GET requests. The net/http
+// adaptor of Fiber buffers the response until the handler returns, which
+// would hold back event streams forever, so once the handler flushes its
+// writes go to the body stream writer of fasthttp instead.
+type streamWriter struct {
+	header http.Header
+	status int
+
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	started bool
+	chunks  chan []byte
+	// ready is closed by the first flush and done when the handler returns
+	ready chan struct{}
+	done  chan struct{}
+}
+
+func newStreamWriter() *streamWriter {
+	return &streamWriter{
+		header: make(http.Header),
+		chunks: make(chan []byte),
+		ready:  make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// Header implements http.ResponseWriter
+func (w *streamWriter) Header() http.Header {
+	return w.header
+}
+
+// WriteHeader implements http.ResponseWriter
+func (w *streamWriter) WriteHeader(status int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+// Write implements http.ResponseWriter
+func (w *streamWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if !w.started {
+		defer w.mu.Unlock()
+		return w.buf.Write(p)
+	}
+	w.mu.Unlock()
+
+	w.chunks <- append([]byte(nil), p...)
+	return len(p), nil
+}
+
+// Flush implements http.Flusher. The first flush starts the stream.
+func (w *streamWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if !w.started {
+		w.started = true
+		close(w.ready)
+	}
+}
+
+// finish is called once the handler returned
+func (w *streamWriter) finish() {
+	close(w.chunks)
+	close(w.done)
+}
+
+// respond copies the status and headers of the handler to c
+func (w *streamWriter) respond(c *fiber.Ctx) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.status != 0 {
+		c.Status(w.status)
+	}
+	for name, values := range w.header {
+		for _, value := range values {
+			c.Response().Header.Add(name, value)
+		}
+	}
+}
+
+// stream writes the chunks of the handler until it returns or the client
+// goes away, in which case cancel stops the handler
+func (w *streamWriter) stream(bw *bufio.Writer, cancel context.CancelFunc) {
+	defer cancel()
+
+	ticker := time.NewTicker(streamKeepAlive)
+	defer ticker.Stop()
+
+	chunk := w.buf.Bytes()
+	for {
+		if chunk != nil {
+			if _, err := bw.Write(chunk); err != nil {
+				break
+			}
+		}
+		if err := bw.Flush(); err != nil {
+			break
+		}
+
+		var ok bool
+		select {
+		case chunk, ok = <-w.chunks:
+			if !ok {
+				return
+			}
+		case <-ticker.C:
+			chunk = []byte(": keep-alive\n\n")
+		}
+	}
+
+	// The client is gone, let the handler return
+	cancel()
+	for range w.chunks {
+	}
+}
+
+// handleGet serves GET requests, which open the event stream of a session,
+// with writes reaching the client as they are flushed
+func (a *Adapter) handleGet(c *fiber.Ctx) error {
+	req, err := adaptor.ConvertRequest(c, true)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := newStreamWriter()
+	go func() {
+		defer w.finish()
+		a.transport.Handler().ServeHTTP(w, req.WithContext(ctx))
+	}()
+
+	select {
+	case <-w.ready:
+	case <-w.done:
+		w.mu.Lock()
+		started := w.started
+		w.mu.Unlock()
+		if !started {
+			cancel()
+			w.respond(c)
+			return c.Send(w.buf.Bytes())
+		}
+	}
+
+	w.respond(c)
+	c.Context().SetBodyStreamWriter(func(bw *bufio.Writer) {
+		w.stream(bw, cancel)
+	})
+	return nil
+}
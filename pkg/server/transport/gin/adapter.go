@@ -34,6 +34,7 @@ func (a *Adapter) RegisterRoutes(r *gin.Engine) {
 		c.String(200, "OK")
 	})
 
-	// MCP endpoint
+	// MCP endpoint, where GET opens the event stream of a session
 	r.POST("/mcp", a.Handler())
+	r.GET("/mcp", a.Handler())
 }
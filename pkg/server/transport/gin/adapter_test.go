@@ -0,0 +1,75 @@
+package gin
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/server"
+	transporterrors "github.com/harriteja/mcp-go-sdk/pkg/server/transport/errors"
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+)
+
+func post(t *testing.T, url, sessionID, body string) *http.Response {
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if sessionID != "" {
+		req.Header.Set(transporterrors.HeaderSessionID, sessionID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return resp
+}
+
+func TestAdapter_EventStream(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	srv, err := server.New(&server.Options{Name: "test-server", Version: "1.0.0", Logger: types.NewNoOpLogger()})
+	require.NoError(t, err)
+
+	engine := gin.New()
+	New(srv, types.NewNoOpLogger()).RegisterRoutes(engine)
+	ts := httptest.NewServer(engine)
+	defer ts.Close()
+
+	resp := post(t, ts.URL+"/mcp", "", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18"}}`)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	sessionID := resp.Header.Get(transporterrors.HeaderSessionID)
+	require.NotEmpty(t, sessionID)
+	resp = post(t, ts.URL+"/mcp", sessionID, `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	resp.Body.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/mcp", nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set(transporterrors.HeaderSessionID, sessionID)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	// Notifications sent outside a request arrive on the stream
+	require.NoError(t, srv.AddTool(types.Tool{Name: "echo"}, func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return args, nil
+	}))
+	reader := bufio.NewReader(resp.Body)
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		if strings.HasPrefix(line, "data: ") {
+			assert.Contains(t, line, types.NotificationToolsListChanged)
+			break
+		}
+	}
+}
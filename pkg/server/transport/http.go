@@ -1,14 +1,12 @@
 package transport
 
 import (
-	"context"
-	"encoding/json"
-	"fmt"
+	"io"
 	"net/http"
-
-	"github.com/pkg/errors"
+	"sync"
 
 	"github.com/harriteja/mcp-go-sdk/pkg/server"
+	transporterrors "github.com/harriteja/mcp-go-sdk/pkg/server/transport/errors"
 	"github.com/harriteja/mcp-go-sdk/pkg/types"
 )
 
@@ -24,10 +22,17 @@ func (f HTTPHandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	f(w, r)
 }
 
-// HTTPTransport provides HTTP transport for MCP server
+// HTTPTransport provides HTTP transport for MCP server. Clients post
+// JSON-RPC messages and receive notifications on an event stream opened with
+// a GET request accepting text/event-stream.
 type HTTPTransport struct {
-	server *server.Server
-	logger types.Logger
+	server     *server.Server
+	dispatcher *server.Dispatcher
+	logger     types.Logger
+
+	// streams holds the open event streams, keyed by session ID
+	streamsMu sync.Mutex
+	streams   map[string]*eventStream
 }
 
 // NewHTTPTransport creates a new HTTP transport
@@ -37,8 +42,10 @@ func NewHTTPTransport(srv *server.Server, logger types.Logger) *HTTPTransport {
 	}
 
 	return &HTTPTransport{
-		server: srv,
-		logger: logger,
+		server:     srv,
+		dispatcher: server.NewDispatcher(srv),
+		logger:     logger,
+		streams:    make(map[string]*eventStream),
 	}
 }
 
@@ -56,151 +63,92 @@ func (t *HTTPTransport) Handler() HTTPHandler {
 	})
 }
 
+// handlePost handles a single JSON-RPC message. Requests are bound to their
+// session by the Mcp-Session-Id header, which the response to initialize
+// carries.
 func (t *HTTPTransport) handlePost(w http.ResponseWriter, r *http.Request) {
-	var req struct {
-		Method string          `json:"method"`
-		Params json.RawMessage `json:"params"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		t.writeError(w, errors.Wrap(err, "failed to decode request"))
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		t.logger.Error(r.Context(), "http", "readBody", "Failed to read request body: "+err.Error())
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
 		return
 	}
 
-	var result interface{}
-	var err error
-
-	switch req.Method {
-	case "initialize":
-		var params types.InitializeRequest
-		if err := json.Unmarshal(req.Params, &params); err != nil {
-			t.writeError(w, errors.Wrap(err, "failed to unmarshal initialize params"))
-			return
-		}
-		result, err = t.server.Initialize(r.Context(), &params)
-
-	case "initialized":
-		var notification types.InitializedNotification
-		if err := json.Unmarshal(req.Params, &notification); err != nil {
-			t.writeError(w, errors.Wrap(err, "failed to unmarshal initialized params"))
-			return
-		}
-		err = t.server.Initialized(r.Context(), &notification)
-		// No result expected for notifications
-
-	case "ping":
-		var params types.PingRequest
-		if err := json.Unmarshal(req.Params, &params); err != nil {
-			t.writeError(w, errors.Wrap(err, "failed to unmarshal ping params"))
+	conn := server.NewConnection()
+	conn.SetNotifier(&httpNotifier{transport: t, conn: conn})
+	if sessionID := r.Header.Get(transporterrors.HeaderSessionID); sessionID != "" {
+		session, ok := t.server.Session(sessionID)
+		if !ok {
+			http.Error(w, "Session not found", http.StatusNotFound)
 			return
 		}
-		result, err = t.server.Ping(r.Context(), &params)
-
-	case "cancel":
-		var params types.CancelRequest
-		if err := json.Unmarshal(req.Params, &params); err != nil {
-			t.writeError(w, errors.Wrap(err, "failed to unmarshal cancel params"))
-			return
-		}
-		err = t.server.Cancel(r.Context(), &params)
-		// No result expected for notifications
-
-	case "listTools":
-		result, err = t.server.ListTools(r.Context())
-
-	case "callTool":
-		var params struct {
-			Name string                 `json:"name"`
-			Args map[string]interface{} `json:"args"`
-		}
-		if err := json.Unmarshal(req.Params, &params); err != nil {
-			t.writeError(w, errors.Wrap(err, "failed to unmarshal call tool params"))
-			return
-		}
-		result, err = t.server.CallTool(r.Context(), params.Name, params.Args)
-
-	case "listPrompts":
-		result, err = t.server.ListPrompts(r.Context())
-
-	case "getPrompt":
-		var params struct {
-			Name string                 `json:"name"`
-			Args map[string]interface{} `json:"args"`
-		}
-		if err := json.Unmarshal(req.Params, &params); err != nil {
-			t.writeError(w, errors.Wrap(err, "failed to unmarshal get prompt params"))
-			return
-		}
-		result, err = t.server.GetPrompt(r.Context(), params.Name, params.Args)
+		conn.Bind(session)
+	}
 
-	case "listResources":
-		result, err = t.server.ListResources(r.Context())
+	// Clients accepting events get the notifications of their request on
+	// the response
+	ctx := server.WithConnection(r.Context(), conn)
+	var stream *responseStream
+	if acceptsEvents(r) {
+		stream = &responseStream{w: w, conn: conn}
+		ctx = server.WithNotifier(ctx, stream)
+	}
 
-	case "readResource":
-		var params struct {
-			URI string `json:"uri"`
-		}
-		if err := json.Unmarshal(req.Params, &params); err != nil {
-			t.writeError(w, errors.Wrap(err, "failed to unmarshal read resource params"))
+	resp, err := t.dispatcher.Handle(ctx, body)
+	if stream != nil {
+		if events := stream.finish(); events != nil {
+			t.finishStream(r, events, resp, err)
 			return
 		}
-		data, mimeType, err := t.server.ReadResource(r.Context(), params.URI)
-		if err != nil {
-			t.writeError(w, err)
-			return
-		}
-		w.Header().Set("Content-Type", mimeType)
-		if _, err := w.Write(data); err != nil {
-			t.logger.Error(r.Context(), "http", "writeData", "Failed to write data: "+err.Error())
-		}
+	}
+	if err != nil {
+		t.logger.Error(r.Context(), "http", "handle", "Failed to handle message: "+err.Error())
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
+	}
 
-	case "listResourceTemplates":
-		result, err = t.server.ListResourceTemplates(r.Context())
-
-	default:
-		t.writeError(w, fmt.Errorf("unknown method: %s", req.Method))
-		return
+	if session := conn.Session(); session != nil {
+		w.Header().Set(transporterrors.HeaderSessionID, session.ID())
 	}
 
-	if err != nil {
-		t.writeError(w, err)
+	// Notifications and responses are acknowledged without a body
+	if resp == nil {
+		w.WriteHeader(http.StatusAccepted)
 		return
 	}
 
-	t.writeJSON(w, result)
-}
-
-func (t *HTTPTransport) handleGet(w http.ResponseWriter, r *http.Request) {
-	// Health check endpoint
-	w.WriteHeader(http.StatusOK)
-	if _, err := w.Write([]byte("OK")); err != nil {
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(resp); err != nil {
 		t.logger.Error(r.Context(), "http", "writeData", "Failed to write data: "+err.Error())
 	}
 }
 
-func (t *HTTPTransport) writeJSON(w http.ResponseWriter, v interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(v); err != nil {
-		t.logger.Error(context.Background(), "http", "writeJSON", "Failed to encode response: "+err.Error())
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+// finishStream sends the response to a request whose notifications were
+// streamed as events
+func (t *HTTPTransport) finishStream(r *http.Request, events *eventStream, resp []byte, err error) {
+	if err != nil {
+		t.logger.Error(r.Context(), "http", "handle", "Failed to handle message: "+err.Error())
+		return
+	}
+	if resp == nil {
+		return
+	}
+	if err := events.send(resp); err != nil {
+		t.logger.Error(r.Context(), "http", "writeData", "Failed to write data: "+err.Error())
 	}
 }
 
-func (t *HTTPTransport) writeError(w http.ResponseWriter, err error) {
-	t.logger.Error(context.Background(), "http", "writeError", "Request error: "+err.Error())
-
-	mcpErr, ok := err.(*types.Error)
-	if !ok {
-		mcpErr = &types.Error{
-			Code:    http.StatusInternalServerError,
-			Message: err.Error(),
-		}
+// handleGet opens an event stream for clients accepting one, and otherwise
+// serves as a health check
+func (t *HTTPTransport) handleGet(w http.ResponseWriter, r *http.Request) {
+	if acceptsEvents(r) {
+		t.handleEventStream(w, r)
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(mcpErr.Code)
-	if err := json.NewEncoder(w).Encode(mcpErr); err != nil {
-		t.logger.Error(context.Background(), "http", "writeError", "Failed to encode error response: "+err.Error())
+	// Health check endpoint
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte("OK")); err != nil {
+		t.logger.Error(r.Context(), "http", "writeData", "Failed to write data: "+err.Error())
 	}
 }
@@ -5,7 +5,11 @@ import (
 	"context"
 	"encoding/json"
 	"io"
+	"sync"
 
+	"github.com/pkg/errors"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
 	"github.com/harriteja/mcp-go-sdk/pkg/server"
 	"github.com/harriteja/mcp-go-sdk/pkg/types"
 )
@@ -22,10 +26,13 @@ type Options struct {
 
 // Transport implements stdio transport
 type Transport struct {
-	srv    *server.Server
-	reader *bufio.Reader
-	writer *bufio.Writer
-	logger types.Logger
+	server     *server.Server
+	dispatcher *server.Dispatcher
+	conn       *server.Connection
+	reader     *bufio.Reader
+	writer     *bufio.Writer
+	writeMu    sync.Mutex
+	logger     types.Logger
 }
 
 // New creates a new stdio transport
@@ -34,21 +41,53 @@ func New(srv *server.Server, opts Options) *Transport {
 		opts.Logger = types.NewNoOpLogger()
 	}
 
-	return &Transport{
-		srv:    srv,
-		reader: bufio.NewReader(opts.Reader),
-		writer: bufio.NewWriter(opts.Writer),
-		logger: opts.Logger,
+	t := &Transport{
+		server:     srv,
+		dispatcher: server.NewDispatcher(srv),
+		conn:       server.NewConnection(),
+		reader:     bufio.NewReader(opts.Reader),
+		writer:     bufio.NewWriter(opts.Writer),
+		logger:     opts.Logger,
 	}
+	t.conn.SetNotifier(t)
+	return t
+}
+
+// Notify implements server.Notifier by writing the notification as one line,
+// interleaved with the replies
+func (t *Transport) Notify(ctx context.Context, notification *jsonrpc.Notification) error {
+	return t.send(notification)
 }
 
-// Start starts the transport
+// SendRequest implements server.RequestSender. The response comes back on
+// the input stream.
+func (t *Transport) SendRequest(ctx context.Context, request *jsonrpc.Request) error {
+	return t.send(request)
+}
+
+// send writes a message sent by the server on its own accord
+func (t *Transport) send(msg interface{}) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode message")
+	}
+	return t.writeLine(data)
+}
+
+// Start starts the transport. Every line read is one JSON-RPC message and
+// every reply is written as one line. The stream is a single connection, so
+// all messages belong to the session created by its initialize request.
+// Requests are handled concurrently so a slow request can still be
+// cancelled; Start waits for them before returning. The session ends with
+// the stream.
 func (t *Transport) Start() error {
-	ctx := context.Background()
+	ctx := server.WithConnection(context.Background(), t.conn)
 	t.logger.Info(ctx, "stdio", "start", "Starting StdIO transport")
+	defer t.closeSession()
+	defer t.dispatcher.Wait()
 
 	for {
-		// Read request
+		// Read message
 		line, err := t.reader.ReadBytes('\n')
 		if err != nil {
 			if err == io.EOF {
@@ -59,219 +98,35 @@ func (t *Transport) Start() error {
 			return err
 		}
 
-		// Parse request
-		var req struct {
-			Method string          `json:"method"`
-			Params json.RawMessage `json:"params,omitempty"`
-		}
-		if err := json.Unmarshal(line, &req); err != nil {
-			t.logger.Error(ctx, "stdio", "parse", "Failed to parse request: "+err.Error())
-			t.writeError(500, "failed to parse request: "+err.Error())
-			continue
-		}
-
-		t.logger.Info(ctx, "stdio", "request", "Received request: "+req.Method)
-
-		// Handle request
-		switch req.Method {
-		case "initialize":
-			var params types.InitializeRequest
-			if err := json.Unmarshal(req.Params, &params); err != nil {
-				t.logger.Error(ctx, "stdio", "initialize", "Invalid initialize params: "+err.Error())
-				t.writeError(400, "invalid initialize params: "+err.Error())
-				continue
-			}
-			resp, err := t.srv.Initialize(ctx, &params)
-			if err != nil {
-				t.logger.Error(ctx, "stdio", "initialize", "Failed to initialize: "+err.Error())
-				t.writeError(500, err.Error())
-				continue
-			}
-			t.writeResponse(resp)
-
-		case "initialized":
-			var notification types.InitializedNotification
-			if err := json.Unmarshal(req.Params, &notification); err != nil {
-				t.logger.Error(ctx, "stdio", "initialized", "Invalid initialized params: "+err.Error())
-				t.writeError(400, "invalid initialized params: "+err.Error())
-				continue
-			}
-			err := t.srv.Initialized(ctx, &notification)
-			if err != nil {
-				t.logger.Error(ctx, "stdio", "initialized", "Failed to process initialized notification: "+err.Error())
-				t.writeError(500, err.Error())
-				continue
-			}
-			// No response needed for notifications
-
-		case "ping":
-			var params types.PingRequest
-			if err := json.Unmarshal(req.Params, &params); err != nil {
-				t.logger.Error(ctx, "stdio", "ping", "Invalid ping params: "+err.Error())
-				t.writeError(400, "invalid ping params: "+err.Error())
-				continue
-			}
-			resp, err := t.srv.Ping(ctx, &params)
-			if err != nil {
-				t.logger.Error(ctx, "stdio", "ping", "Failed to ping: "+err.Error())
-				t.writeError(500, err.Error())
-				continue
-			}
-			t.writeResponse(resp)
-
-		case "cancel":
-			var params types.CancelRequest
-			if err := json.Unmarshal(req.Params, &params); err != nil {
-				t.logger.Error(ctx, "stdio", "cancel", "Invalid cancel params: "+err.Error())
-				t.writeError(400, "invalid cancel params: "+err.Error())
-				continue
-			}
-			err := t.srv.Cancel(ctx, &params)
-			if err != nil {
-				t.logger.Error(ctx, "stdio", "cancel", "Failed to cancel: "+err.Error())
-				t.writeError(500, err.Error())
-				continue
-			}
-			// No response needed for notifications
-
-		case "listTools":
-			tools, err := t.srv.ListTools(ctx)
-			if err != nil {
-				t.logger.Error(ctx, "stdio", "listTools", "Failed to list tools: "+err.Error())
-				t.writeError(500, err.Error())
-				continue
-			}
-			t.writeResponse(tools)
-
-		case "callTool":
-			var params struct {
-				Name string                 `json:"name"`
-				Args map[string]interface{} `json:"args"`
-			}
-			if err := json.Unmarshal(req.Params, &params); err != nil {
-				t.logger.Error(ctx, "stdio", "callTool", "Invalid callTool params: "+err.Error())
-				t.writeError(400, "invalid callTool params: "+err.Error())
-				continue
-			}
-			result, err := t.srv.CallTool(ctx, params.Name, params.Args)
-			if err != nil {
-				t.logger.Error(ctx, "stdio", "callTool", "Failed to call tool: "+err.Error())
-				if mcpErr, ok := err.(*types.Error); ok {
-					t.writeError(mcpErr.Code, mcpErr.Message)
-				} else {
-					t.writeError(500, err.Error())
-				}
-				continue
-			}
-			t.writeResponse(result)
-
-		case "listPrompts":
-			result, err := t.srv.ListPrompts(ctx)
-			if err != nil {
-				t.logger.Error(ctx, "stdio", "listPrompts", "Failed to list prompts: "+err.Error())
-				t.writeError(500, err.Error())
-				continue
-			}
-			t.writeResponse(result)
-
-		case "getPrompt":
-			var params struct {
-				Name string                 `json:"name"`
-				Args map[string]interface{} `json:"args"`
-			}
-			if err := json.Unmarshal(req.Params, &params); err != nil {
-				t.logger.Error(ctx, "stdio", "getPrompt", "Invalid getPrompt params: "+err.Error())
-				t.writeError(400, "invalid getPrompt params: "+err.Error())
-				continue
-			}
-			result, err := t.srv.GetPrompt(ctx, params.Name, params.Args)
-			if err != nil {
-				t.logger.Error(ctx, "stdio", "getPrompt", "Failed to get prompt: "+err.Error())
-				t.writeError(500, err.Error())
-				continue
-			}
-			t.writeResponse(result)
-
-		case "listResources":
-			result, err := t.srv.ListResources(ctx)
-			if err != nil {
-				t.logger.Error(ctx, "stdio", "listResources", "Failed to list resources: "+err.Error())
-				t.writeError(500, err.Error())
-				continue
-			}
-			t.writeResponse(result)
-
-		case "readResource":
-			var params struct {
-				URI string `json:"uri"`
-			}
-			if err := json.Unmarshal(req.Params, &params); err != nil {
-				t.logger.Error(ctx, "stdio", "readResource", "Invalid readResource params: "+err.Error())
-				t.writeError(400, "invalid readResource params: "+err.Error())
-				continue
-			}
-			data, mimeType, err := t.srv.ReadResource(ctx, params.URI)
-			if err != nil {
-				t.logger.Error(ctx, "stdio", "readResource", "Failed to read resource: "+err.Error())
-				t.writeError(500, err.Error())
-				continue
-			}
-			result := struct {
-				Data     []byte `json:"data"`
-				MimeType string `json:"mimeType"`
-			}{
-				Data:     data,
-				MimeType: mimeType,
-			}
-			t.writeResponse(result)
-
-		case "listResourceTemplates":
-			result, err := t.srv.ListResourceTemplates(ctx)
-			if err != nil {
-				t.logger.Error(ctx, "stdio", "listResourceTemplates", "Failed to list resource templates: "+err.Error())
-				t.writeError(500, err.Error())
-				continue
-			}
-			t.writeResponse(result)
-
-		default:
-			t.logger.Error(ctx, "stdio", "method", "Unknown method: "+req.Method)
-			t.writeError(404, "unknown method: "+req.Method)
-		}
+		t.dispatcher.HandleAsync(ctx, line, t.write)
 	}
 }
 
-func (t *Transport) writeResponse(v interface{}) {
-	resp := struct {
-		Result interface{} `json:"result"`
-	}{
-		Result: v,
+// closeSession closes the session bound to the stream, if any
+func (t *Transport) closeSession() {
+	if session := t.conn.Session(); session != nil {
+		// The session may already have been closed by the server
+		_ = t.server.CloseSession(session.ID())
 	}
+}
 
-	if err := json.NewEncoder(t.writer).Encode(resp); err != nil {
-		t.logger.Error(context.Background(), "stdio", "writeResponse", "Failed to write response: "+err.Error())
-		return
-	}
-	if err := t.writer.Flush(); err != nil {
-		t.logger.Error(context.Background(), "stdio", "writeResponse", "Failed to flush response: "+err.Error())
+// write writes a reply, logging failures since nobody waits for them
+func (t *Transport) write(data []byte) {
+	if err := t.writeLine(data); err != nil {
+		t.logger.Error(context.Background(), "stdio", "write", "Failed to write response: "+err.Error())
 	}
 }
 
-func (t *Transport) writeError(code int, message string) {
-	resp := struct {
-		Error *types.Error `json:"error"`
-	}{
-		Error: &types.Error{
-			Code:    code,
-			Message: message,
-		},
-	}
+// writeLine writes a single encoded message followed by a newline
+func (t *Transport) writeLine(data []byte) error {
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
 
-	if err := json.NewEncoder(t.writer).Encode(resp); err != nil {
-		t.logger.Error(context.Background(), "stdio", "writeError", "Failed to write error: "+err.Error())
-		return
+	if _, err := t.writer.Write(append(data, '\n')); err != nil {
+		return errors.Wrap(err, "failed to write message")
 	}
 	if err := t.writer.Flush(); err != nil {
-		t.logger.Error(context.Background(), "stdio", "writeError", "Failed to flush error: "+err.Error())
+		return errors.Wrap(err, "failed to flush message")
 	}
+	return nil
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
 	"github.com/harriteja/mcp-go-sdk/pkg/server"
 	"github.com/harriteja/mcp-go-sdk/pkg/types"
 )
@@ -40,27 +41,24 @@ func TestTransport_Initialize(t *testing.T) {
 	}()
 
 	// Write initialize request
-	req := struct {
-		Method string                  `json:"method"`
-		Params types.InitializeRequest `json:"params"`
-	}{
-		Method: "initialize",
-		Params: types.InitializeRequest{
-			ProtocolVersion: "1.0",
-			ClientInfo: types.Implementation{
-				Name:    "test-client",
-				Version: "1.0.0",
-			},
+	req, err := jsonrpc.NewRequest(jsonrpc.NewIntID(1), "initialize", types.InitializeRequest{
+		ProtocolVersion: "1.0",
+		ClientInfo: types.Implementation{
+			Name:    "test-client",
+			Version: "1.0.0",
 		},
-	}
+	})
+	require.NoError(t, err)
 	if err := json.NewEncoder(inputWriter).Encode(req); err != nil {
 		t.Fatal(err)
 	}
 
 	// Read response
 	var resp struct {
-		Result types.InitializeResponse `json:"result"`
-		Error  *types.Error             `json:"error,omitempty"`
+		JSONRPC string                   `json:"jsonrpc"`
+		ID      jsonrpc.ID               `json:"id"`
+		Result  types.InitializeResponse `json:"result"`
+		Error   *jsonrpc.ErrorObject     `json:"error,omitempty"`
 	}
 	if err := json.NewDecoder(outputReader).Decode(&resp); err != nil {
 		t.Fatal(err)
@@ -68,6 +66,8 @@ func TestTransport_Initialize(t *testing.T) {
 
 	// Verify response
 	assert.Nil(t, resp.Error)
+	assert.Equal(t, jsonrpc.Version, resp.JSONRPC)
+	assert.Equal(t, "1", resp.ID.String())
 	assert.Equal(t, "test-server", resp.Result.ServerInfo.Name)
 	assert.Equal(t, "1.0.0", resp.Result.ServerInfo.Version)
 
@@ -122,20 +122,28 @@ func TestTransport_ListTools(t *testing.T) {
 		done <- transport.Start()
 	}()
 
+	// Requests other than ping need an initialized session
+	initReq, err := jsonrpc.NewRequest(jsonrpc.NewIntID(1), types.MethodInitialize, types.InitializeRequest{
+		ProtocolVersion: types.LatestProtocolVersion,
+	})
+	require.NoError(t, err)
+	require.NoError(t, json.NewEncoder(inputWriter).Encode(initReq))
+	var initResp jsonrpc.Message
+	require.NoError(t, json.NewDecoder(outputReader).Decode(&initResp))
+	require.Nil(t, initResp.Error)
+
 	// Write list tools request
-	req := struct {
-		Method string `json:"method"`
-	}{
-		Method: "listTools",
-	}
+	req, err := jsonrpc.NewRequest(jsonrpc.NewStringID("list-1"), types.MethodToolsList, nil)
+	require.NoError(t, err)
 	if err := json.NewEncoder(inputWriter).Encode(req); err != nil {
 		t.Fatal(err)
 	}
 
 	// Read response
 	var resp struct {
-		Result []types.Tool `json:"result"`
-		Error  *types.Error `json:"error,omitempty"`
+		ID     jsonrpc.ID            `json:"id"`
+		Result types.ListToolsResult `json:"result"`
+		Error  *jsonrpc.ErrorObject  `json:"error,omitempty"`
 	}
 	if err := json.NewDecoder(outputReader).Decode(&resp); err != nil {
 		t.Fatal(err)
@@ -143,9 +151,10 @@ func TestTransport_ListTools(t *testing.T) {
 
 	// Verify response
 	assert.Nil(t, resp.Error)
-	assert.Len(t, resp.Result, 1)
-	assert.Equal(t, "test-tool", resp.Result[0].Name)
-	assert.Equal(t, "A test tool", resp.Result[0].Description)
+	assert.Equal(t, `"list-1"`, resp.ID.String())
+	require.Len(t, resp.Result.Tools, 1)
+	assert.Equal(t, "test-tool", resp.Result.Tools[0].Name)
+	assert.Equal(t, "A test tool", resp.Result.Tools[0].Description)
 
 	// Close input to signal EOF
 	inputWriter.Close()
@@ -195,8 +204,9 @@ func TestTransport_Error(t *testing.T) {
 
 	// Read error response
 	var resp struct {
-		Result interface{}  `json:"result,omitempty"`
-		Error  *types.Error `json:"error"`
+		ID     jsonrpc.ID           `json:"id"`
+		Result interface{}          `json:"result,omitempty"`
+		Error  *jsonrpc.ErrorObject `json:"error"`
 	}
 	if err := json.NewDecoder(outputReader).Decode(&resp); err != nil {
 		t.Fatal(err)
@@ -204,8 +214,9 @@ func TestTransport_Error(t *testing.T) {
 
 	// Verify error
 	assert.NotNil(t, resp.Error)
-	assert.Equal(t, 500, resp.Error.Code)
-	assert.Contains(t, resp.Error.Message, "failed to parse request")
+	assert.True(t, resp.ID.IsZero())
+	assert.Equal(t, jsonrpc.CodeParseError, resp.Error.Code)
+	assert.Contains(t, resp.Error.Message, "parse error")
 
 	// Close input to signal EOF
 	inputWriter.Close()
@@ -222,3 +233,150 @@ func TestTransport_Error(t *testing.T) {
 	outputReader.Close()
 	outputWriter.Close()
 }
+
+func TestTransport_MethodNotFound(t *testing.T) {
+	// Create test server
+	srv, err := server.New(&server.Options{
+		Name:    "test-server",
+		Version: "1.0.0",
+	})
+	require.NoError(t, err)
+
+	// Create test pipes
+	inputReader, inputWriter := io.Pipe()
+	outputReader, outputWriter := io.Pipe()
+
+	// Create transport
+	transport := New(srv, Options{
+		Reader: inputReader,
+		Writer: outputWriter,
+		Logger: types.NewNoOpLogger(),
+	})
+
+	// Start transport in goroutine
+	done := make(chan error)
+	go func() {
+		done <- transport.Start()
+	}()
+
+	// Notifications are never answered, even when the method is unknown
+	notification, err := jsonrpc.NewNotification("unknown/notification", nil)
+	require.NoError(t, err)
+	require.NoError(t, json.NewEncoder(inputWriter).Encode(notification))
+
+	req, err := jsonrpc.NewRequest(jsonrpc.NewIntID(7), "unknown", nil)
+	require.NoError(t, err)
+	require.NoError(t, json.NewEncoder(inputWriter).Encode(req))
+
+	// The first response must belong to the request
+	var resp jsonrpc.Response
+	if err := json.NewDecoder(outputReader).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "7", resp.ID.String())
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, jsonrpc.CodeMethodNotFound, resp.Error.Code)
+
+	// Close input to signal EOF
+	inputWriter.Close()
+
+	// Wait for transport to stop
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("transport did not stop")
+	}
+
+	// Clean up
+	outputReader.Close()
+	outputWriter.Close()
+}
+
+func TestTransport_Notify(t *testing.T) {
+	srv, err := server.New(&server.Options{
+		Name:    "test-server",
+		Version: "1.0.0",
+		Logger:  types.NewNoOpLogger(),
+	})
+	require.NoError(t, err)
+
+	// The tool notifies the client before answering
+	require.NoError(t, srv.AddTool(types.Tool{Name: "notify"}, func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		session, ok := server.SessionFromContext(ctx)
+		require.True(t, ok)
+		return "done", session.Notify(ctx, "notifications/test", map[string]string{"step": "one"})
+	}))
+
+	inputReader, inputWriter := io.Pipe()
+	outputReader, outputWriter := io.Pipe()
+	transport := New(srv, Options{
+		Reader: inputReader,
+		Writer: outputWriter,
+		Logger: types.NewNoOpLogger(),
+	})
+
+	done := make(chan error)
+	go func() {
+		done <- transport.Start()
+	}()
+
+	enc := json.NewEncoder(inputWriter)
+	dec := json.NewDecoder(outputReader)
+
+	initReq, err := jsonrpc.NewRequest(jsonrpc.NewIntID(1), types.MethodInitialize, types.InitializeRequest{
+		ProtocolVersion: types.LatestProtocolVersion,
+	})
+	require.NoError(t, err)
+	require.NoError(t, enc.Encode(initReq))
+	var msg jsonrpc.Message
+	require.NoError(t, dec.Decode(&msg))
+	require.Nil(t, msg.Error)
+
+	initialized, err := jsonrpc.NewNotification(types.NotificationInitialized, nil)
+	require.NoError(t, err)
+	require.NoError(t, enc.Encode(initialized))
+
+	require.Eventually(t, func() bool {
+		sessions := srv.Sessions()
+		return len(sessions) == 1 && sessions[0].IsInitialized()
+	}, time.Second, 10*time.Millisecond)
+
+	// Registering a tool now notifies the initialized session. The pipe
+	// blocks until the notification is read.
+	go func() {
+		assert.NoError(t, srv.AddTool(types.Tool{Name: "other"}, func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			return nil, nil
+		}))
+	}()
+	msg = jsonrpc.Message{}
+	require.NoError(t, dec.Decode(&msg))
+	assert.True(t, msg.IsNotification())
+	assert.Equal(t, types.NotificationToolsListChanged, msg.Method)
+
+	callReq, err := jsonrpc.NewRequest(jsonrpc.NewIntID(2), types.MethodToolsCall, types.CallToolRequest{Name: "notify"})
+	require.NoError(t, err)
+	require.NoError(t, enc.Encode(callReq))
+
+	msg = jsonrpc.Message{}
+	require.NoError(t, dec.Decode(&msg))
+	assert.True(t, msg.IsNotification())
+	assert.Equal(t, "notifications/test", msg.Method)
+	assert.JSONEq(t, `{"step":"one"}`, string(msg.Params))
+
+	msg = jsonrpc.Message{}
+	require.NoError(t, dec.Decode(&msg))
+	assert.True(t, msg.IsResponse())
+	assert.Nil(t, msg.Error)
+
+	inputWriter.Close()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("transport did not stop")
+	}
+	outputReader.Close()
+	outputWriter.Close()
+}
@@ -3,12 +3,12 @@ package websocket
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
 	"github.com/harriteja/mcp-go-sdk/pkg/types"
 )
 
@@ -18,6 +18,41 @@ type Message struct {
 	Payload json.RawMessage `json:"payload"`
 }
 
+// frameWriter serializes the writes on a connection, since
+// gorilla/websocket does not allow concurrent writers. HandleConnection
+// creates one per connection and passes it to handlers through the context.
+type frameWriter struct {
+	mu sync.Mutex
+	ws *websocket.Conn
+}
+
+// write writes one text frame
+func (w *frameWriter) write(data []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.ws.WriteMessage(websocket.TextMessage, data)
+}
+
+// writeJSON encodes v and writes it as one text frame
+func (w *frameWriter) writeJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return w.write(data)
+}
+
+type frameWriterKey struct{}
+
+// frameWriterFor returns the writer of conn stored in ctx by
+// HandleConnection, or a new one for connections served elsewhere
+func frameWriterFor(ctx context.Context, conn *websocket.Conn) *frameWriter {
+	if w, ok := ctx.Value(frameWriterKey{}).(*frameWriter); ok && w.ws == conn {
+		return w
+	}
+	return &frameWriter{ws: conn}
+}
+
 // Handler handles WebSocket messages
 type Handler interface {
 	// HandleMessage processes incoming messages
@@ -28,6 +63,7 @@ type Handler interface {
 type Server struct {
 	upgrader websocket.Upgrader
 	handlers map[string]Handler
+	rpc      Handler
 	logger   types.Logger
 	mu       sync.RWMutex
 	ctx      context.Context
@@ -91,6 +127,15 @@ func (s *Server) RegisterHandler(msgType string, handler Handler) {
 	s.handlers[msgType] = handler
 }
 
+// RegisterRPCHandler registers the handler for JSON-RPC 2.0 frames. Frames
+// carrying a "jsonrpc" member are passed to it with Type set to the method
+// name and Payload holding the complete frame.
+func (s *Server) RegisterRPCHandler(handler Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rpc = handler
+}
+
 // Start implements Transport.Start
 func (s *Server) Start(ctx context.Context) error {
 	s.logger.Info(ctx, "websocket", "start", "Starting WebSocket transport server")
@@ -151,9 +196,12 @@ func (s *Server) HandleConnection(w http.ResponseWriter, r *http.Request) {
 
 	s.logger.Info(r.Context(), "websocket", "connection", "New WebSocket connection from "+r.RemoteAddr)
 
-	// Create context for the connection
+	// Create context for the connection. Every write goes through writer,
+	// including the replies the MCP handler sends from other goroutines.
 	ctx, cancel := context.WithCancel(s.ctx)
 	defer cancel()
+	writer := &frameWriter{ws: conn}
+	ctx = context.WithValue(ctx, frameWriterKey{}, writer)
 
 	// Handle messages
 	for {
@@ -162,14 +210,27 @@ func (s *Server) HandleConnection(w http.ResponseWriter, r *http.Request) {
 			return
 		default:
 			// Read message
-			var msg Message
-			if err := conn.ReadJSON(&msg); err != nil {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
 				if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 					s.logger.Error(ctx, "websocket", "read", "WebSocket read error: "+err.Error()+" from "+r.RemoteAddr)
 				}
 				return
 			}
 
+			msg, rpc, err := s.decodeFrame(data)
+			if err != nil {
+				s.logger.Error(ctx, "websocket", "read", "Invalid message: "+err.Error()+" from "+r.RemoteAddr)
+				s.writeError(ctx, writer, r, jsonrpc.NewError(jsonrpc.CodeParseError, "parse error: "+err.Error()))
+				continue
+			}
+			if rpc != nil {
+				if err := rpc.HandleMessage(ctx, conn, msg); err != nil {
+					s.logger.Error(ctx, "websocket", "handle", "Failed to handle JSON-RPC message: "+err.Error()+" from "+r.RemoteAddr)
+				}
+				continue
+			}
+
 			// Get handler for message type
 			s.mu.RLock()
 			handler, ok := s.handlers[msg.Type]
@@ -177,29 +238,50 @@ func (s *Server) HandleConnection(w http.ResponseWriter, r *http.Request) {
 
 			if !ok {
 				s.logger.Warn(ctx, "websocket", "handler", "Unknown message type: "+msg.Type+" from "+r.RemoteAddr)
-				if err := conn.WriteJSON(Message{
-					Type:    "error",
-					Payload: json.RawMessage(fmt.Sprintf(`{"message":"unknown message type: %s"}`, msg.Type)),
-				}); err != nil {
-					s.logger.Error(ctx, "websocket", "write", "Failed to write error message: "+err.Error()+" to "+r.RemoteAddr)
-				}
+				s.writeError(ctx, writer, r, jsonrpc.NewError(jsonrpc.CodeInvalidRequest, "invalid request: unknown message type: "+msg.Type))
 				continue
 			}
 
 			// Handle message
 			if err := handler.HandleMessage(ctx, conn, msg); err != nil {
 				s.logger.Error(ctx, "websocket", "handle", "Failed to handle message: "+err.Error()+" of type "+msg.Type+" from "+r.RemoteAddr)
-				if err := conn.WriteJSON(Message{
-					Type:    "error",
-					Payload: json.RawMessage(fmt.Sprintf(`{"message":%q}`, err.Error())),
-				}); err != nil {
-					s.logger.Error(ctx, "websocket", "write", "Failed to write error message: "+err.Error()+" to "+r.RemoteAddr)
-				}
+				s.writeError(ctx, writer, r, jsonrpc.ErrorFrom(err))
 			}
 		}
 	}
 }
 
+// writeError answers a frame that could not be handled with a JSON-RPC error
+// response. Its id is null, since the frame carried no request ID.
+func (s *Server) writeError(ctx context.Context, writer *frameWriter, r *http.Request, errObj *jsonrpc.ErrorObject) {
+	if err := writer.writeJSON(jsonrpc.NewErrorResponse(jsonrpc.ID{}, errObj)); err != nil {
+		s.logger.Error(ctx, "websocket", "write", "Failed to write error message: "+err.Error()+" to "+r.RemoteAddr)
+	}
+}
+
+// decodeFrame decodes a frame into a Message. When a JSON-RPC handler is
+// registered and the frame is a JSON-RPC message, that handler is returned
+// along with the message.
+func (s *Server) decodeFrame(data []byte) (Message, Handler, error) {
+	var frame struct {
+		Message
+		JSONRPC string `json:"jsonrpc"`
+		Method  string `json:"method"`
+	}
+	if err := json.Unmarshal(data, &frame); err != nil {
+		return Message{}, nil, err
+	}
+
+	s.mu.RLock()
+	rpc := s.rpc
+	s.mu.RUnlock()
+
+	if frame.JSONRPC != "" && rpc != nil {
+		return Message{Type: frame.Method, Payload: data}, rpc, nil
+	}
+	return frame.Message, nil, nil
+}
+
 // ServeHTTP implements http.Handler
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	s.HandleConnection(w, r)
@@ -10,8 +10,11 @@ import (
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
+	mcpserver "github.com/harriteja/mcp-go-sdk/pkg/server"
 	"github.com/harriteja/mcp-go-sdk/pkg/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type testHandler struct {
@@ -114,11 +117,13 @@ func TestServer_UnknownMessageType(t *testing.T) {
 	assert.NoError(t, err)
 
 	// Read error response
-	var response Message
+	var response jsonrpc.Response
 	err = conn.ReadJSON(&response)
-	assert.NoError(t, err)
-	assert.Equal(t, "error", response.Type)
-	assert.Contains(t, string(response.Payload), "unknown message type")
+	require.NoError(t, err)
+	assert.True(t, response.ID.IsZero())
+	require.NotNil(t, response.Error)
+	assert.Equal(t, jsonrpc.CodeInvalidRequest, response.Error.Code)
+	assert.Contains(t, response.Error.Message, "unknown message type")
 }
 
 func TestServer_HandlerError(t *testing.T) {
@@ -151,11 +156,170 @@ func TestServer_HandlerError(t *testing.T) {
 	assert.NoError(t, err)
 
 	// Read error response
-	var response Message
+	var response jsonrpc.Response
+	err = conn.ReadJSON(&response)
+	require.NoError(t, err)
+	require.NotNil(t, response.Error)
+	assert.Equal(t, jsonrpc.CodeInternalError, response.Error.Code)
+	assert.Contains(t, response.Error.Message, assert.AnError.Error())
+}
+
+func TestServer_ParseError(t *testing.T) {
+	server := New(Options{
+		Logger: types.NewNoOpLogger(),
+	})
+
+	// Create test server
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	// Create WebSocket client
+	wsURL := "ws" + strings.TrimPrefix(ts.URL, "http")
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// Send malformed frame
+	err = conn.WriteMessage(websocket.TextMessage, []byte(`{"type":`))
+	require.NoError(t, err)
+
+	// Read error response, whose id is null
+	_, data, err := conn.ReadMessage()
+	require.NoError(t, err)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &response))
+	assert.Equal(t, "2.0", response["jsonrpc"])
+	assert.Contains(t, response, "id")
+	assert.Nil(t, response["id"])
+	if assert.IsType(t, map[string]interface{}{}, response["error"]) {
+		assert.Equal(t, float64(jsonrpc.CodeParseError), response["error"].(map[string]interface{})["code"])
+	}
+}
+
+func TestServer_MCPHandlerJSONRPC(t *testing.T) {
+	server := New(Options{
+		Logger: types.NewNoOpLogger(),
+	})
+
+	mcpServer, err := mcpserver.New(&mcpserver.Options{
+		Name:    "test-server",
+		Version: "1.0.0",
+		Logger:  types.NewNoOpLogger(),
+	})
+	assert.NoError(t, err)
+	RegisterMCPHandlers(server, mcpServer)
+
+	// Create test server
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	// Create WebSocket client
+	wsURL := "ws" + strings.TrimPrefix(ts.URL, "http")
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	// Send a JSON-RPC request frame
+	err = conn.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":"2.0","id":"req-1","method":"ping","params":{"timestamp":1}}`))
+	assert.NoError(t, err)
+
+	// The response echoes the request ID
+	var response jsonrpc.Response
 	err = conn.ReadJSON(&response)
 	assert.NoError(t, err)
-	assert.Equal(t, "error", response.Type)
-	assert.Contains(t, string(response.Payload), assert.AnError.Error())
+	assert.Equal(t, jsonrpc.Version, response.JSONRPC)
+	assert.Equal(t, `"req-1"`, response.ID.String())
+	assert.Nil(t, response.Error)
+
+	// Unknown methods are reported with the standard code
+	err = conn.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":"2.0","id":2,"method":"unknown"}`))
+	assert.NoError(t, err)
+
+	err = conn.ReadJSON(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, "2", response.ID.String())
+	if assert.NotNil(t, response.Error) {
+		assert.Equal(t, jsonrpc.CodeMethodNotFound, response.Error.Code)
+	}
+}
+
+func TestServer_MCPHandlerNotify(t *testing.T) {
+	server := New(Options{
+		Logger: types.NewNoOpLogger(),
+	})
+
+	mcpServer, err := mcpserver.New(&mcpserver.Options{
+		Name:    "test-server",
+		Version: "1.0.0",
+		Logger:  types.NewNoOpLogger(),
+	})
+	require.NoError(t, err)
+	RegisterMCPHandlers(server, mcpServer)
+
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(ts.URL, "http")
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18"}}`)))
+	var msg jsonrpc.Message
+	require.NoError(t, conn.ReadJSON(&msg))
+	require.Nil(t, msg.Error)
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)))
+
+	// The notification is processed asynchronously, so wait for the session
+	// to be initialized before changing the tools
+	require.Eventually(t, func() bool {
+		sessions := mcpServer.Sessions()
+		return len(sessions) == 1 && sessions[0].IsInitialized()
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, mcpServer.AddTool(types.Tool{Name: "echo"}, func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return nil, nil
+	}))
+
+	msg = jsonrpc.Message{}
+	require.NoError(t, conn.ReadJSON(&msg))
+	assert.True(t, msg.IsNotification())
+	assert.Equal(t, types.NotificationToolsListChanged, msg.Method)
+}
+
+func TestServer_MCPHandlerConcurrentWrites(t *testing.T) {
+	server := New(Options{
+		Logger: types.NewNoOpLogger(),
+	})
+
+	mcpServer, err := mcpserver.New(&mcpserver.Options{
+		Name:    "test-server",
+		Version: "1.0.0",
+		Logger:  types.NewNoOpLogger(),
+	})
+	require.NoError(t, err)
+	RegisterMCPHandlers(server, mcpServer)
+
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(ts.URL, "http")
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// Replies written by the request goroutines interleave with the error
+	// frames of the read loop, which share the connection writer
+	const n = 50
+	for i := 0; i < n; i++ {
+		require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":"2.0","id":1,"method":"ping"}`)))
+		require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`not json`)))
+	}
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	for i := 0; i < 2*n; i++ {
+		_, _, err := conn.ReadMessage()
+		require.NoError(t, err)
+	}
 }
 
 func TestServer_WriteError(t *testing.T) {
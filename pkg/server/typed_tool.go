@@ -0,0 +1,109 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"github.com/pkg/errors"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonschema"
+	"github.com/harriteja/mcp-go-sdk/pkg/types"
+)
+
+// NewTypedTool describes a tool by the Go types of its input and output. The
+// input schema is generated from In, which must be a struct, and the output
+// schema from Out when it is a struct too, in which case results also carry
+// the value as structured content. See jsonschema.Reflect for the supported
+// struct tags. Arguments are decoded into In and unknown arguments are
+// rejected with an invalid params error.
+//
+// The results can be passed straight to AddTool:
+//
+//	srv.AddTool(server.NewTypedTool("add", "Add two numbers", add))
+//
+// NewTypedTool panics if In or Out cannot be described by a JSON schema,
+// which is a programming error.
+func NewTypedTool[In, Out any](name, description string, fn func(context.Context, In) (Out, error)) (types.Tool, ToolHandler) {
+	inputSchema, err := typedToolSchema[In]()
+	if err != nil {
+		panic("tool " + name + ": invalid input type: " + err.Error())
+	}
+	if inputSchema == nil {
+		panic("tool " + name + ": input type must be a struct")
+	}
+
+	tool := types.Tool{
+		Name:        name,
+		Description: description,
+		InputSchema: inputSchema,
+	}
+	if tool.OutputSchema, err = typedToolSchema[Out](); err != nil {
+		panic("tool " + name + ": invalid output type: " + err.Error())
+	}
+
+	structured := tool.OutputSchema != nil
+	handler := func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		in, err := decodeArguments[In](args)
+		if err != nil {
+			return nil, err
+		}
+		out, err := fn(ctx, in)
+		if err != nil || !structured {
+			return out, err
+		}
+		if v := reflect.ValueOf(out); v.Kind() == reflect.Pointer && v.IsNil() {
+			return nil, nil
+		}
+
+		// Results with an output schema carry the structured value, and its
+		// JSON text for clients that do not read structured content
+		result, err := toolResult(out)
+		if err != nil {
+			return nil, err
+		}
+		result.StructuredContent = out
+		return result, nil
+	}
+	return tool, handler
+}
+
+// typedToolSchema returns the encoded schema of T, or nil when T is not a
+// struct, since tool schemas must describe objects
+func typedToolSchema[T any]() (json.RawMessage, error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, nil
+	}
+
+	schema, err := jsonschema.Reflect(t)
+	if err != nil {
+		return nil, err
+	}
+	return schema.Marshal()
+}
+
+// decodeArguments decodes tool arguments into a value of type T
+func decodeArguments[T any](args map[string]interface{}) (T, error) {
+	var v T
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+
+	data, err := json.Marshal(args)
+	if err != nil {
+		return v, errors.Wrap(err, "failed to encode arguments")
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&v); err != nil {
+		return v, jsonrpc.NewError(jsonrpc.CodeInvalidParams, "invalid arguments: "+err.Error())
+	}
+	return v, nil
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
+)
+
+type addInput struct {
+	A    float64 `json:"a" jsonschema:"description=First operand"`
+	B    float64 `json:"b" jsonschema:"description=Second operand"`
+	Note *string `json:"note"`
+}
+
+type addOutput struct {
+	Sum float64 `json:"sum"`
+}
+
+func TestNewTypedTool(t *testing.T) {
+	srv, d := newTestDispatcher(t)
+	require.NoError(t, srv.AddTool(NewTypedTool("add", "Add two numbers", func(ctx context.Context, in addInput) (addOutput, error) {
+		return addOutput{Sum: in.A + in.B}, nil
+	})))
+
+	tools, err := srv.ListTools(context.Background())
+	require.NoError(t, err)
+	require.Len(t, tools, 1)
+	assert.Equal(t, "Add two numbers", tools[0].Description)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"a": {"type": "number", "description": "First operand"},
+			"b": {"type": "number", "description": "Second operand"},
+			"note": {"type": ["string", "null"]}
+		},
+		"required": ["a", "b"],
+		"additionalProperties": false
+	}`, string(tools[0].InputSchema))
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {"sum": {"type": "number"}},
+		"required": ["sum"],
+		"additionalProperties": false
+	}`, string(tools[0].OutputSchema))
+
+	ctx := initialize(t, d)
+	resp := dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"add","arguments":{"a":1,"b":2}}}`)
+	require.Nil(t, resp.Error)
+	assert.JSONEq(t, `{"content":[{"type":"text","text":"{\"sum\":3}"}],"structuredContent":{"sum":3}}`, string(resp.Result))
+
+	// Unknown and mistyped arguments are rejected before the handler runs
+	for _, args := range []string{`{"a":1,"b":2,"c":3}`, `{"a":"one","b":2}`} {
+		resp = dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"add","arguments":`+args+`}}`)
+		require.NotNil(t, resp.Error, args)
+		assert.Equal(t, jsonrpc.CodeInvalidParams, resp.Error.Code)
+	}
+
+	// Optional arguments may be null
+	resp = dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"add","arguments":{"a":1,"b":2,"note":null}}}`)
+	require.Nil(t, resp.Error)
+	assert.JSONEq(t, `{"content":[{"type":"text","text":"{\"sum\":3}"}],"structuredContent":{"sum":3}}`, string(resp.Result))
+}
+
+type listOutput struct {
+	Items []string          `json:"items"`
+	Note  *string           `json:"note"`
+	Tags  map[string]string `json:"tags"`
+}
+
+func TestNewTypedTool_ZeroOutput(t *testing.T) {
+	srv, d := newTestDispatcher(t)
+	require.NoError(t, srv.AddTool(NewTypedTool("list", "List nothing", func(ctx context.Context, in struct{}) (listOutput, error) {
+		return listOutput{}, nil
+	})))
+
+	// Nil slices, pointers and maps are encoded as null, which the output
+	// schema accepts
+	ctx := initialize(t, d)
+	resp := dispatch(t, ctx, d, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"list"}}`)
+	require.Nil(t, resp.Error)
+	assert.JSONEq(t, `{
+		"content": [{"type": "text", "text": "{\"items\":null,\"note\":null,\"tags\":null}"}],
+		"structuredContent": {"items": null, "note": null, "tags": null}
+	}`, string(resp.Result))
+}
+
+func TestNewTypedTool_Types(t *testing.T) {
+	// Only struct outputs get a schema
+	tool, _ := NewTypedTool("echo", "", func(ctx context.Context, in struct{}) (string, error) {
+		return "", nil
+	})
+	assert.Nil(t, tool.OutputSchema)
+
+	assert.Panics(t, func() {
+		NewTypedTool("bad", "", func(ctx context.Context, in string) (string, error) {
+			return in, nil
+		})
+	})
+}
@@ -0,0 +1,71 @@
+package types
+
+// Types of CompletionReference
+const (
+	RefTypePrompt   = "ref/prompt"
+	RefTypeResource = "ref/resource"
+)
+
+// MaxCompletionValues is the most values a completion result holds
+const MaxCompletionValues = 100
+
+// CompletionsCapability represents completion-related capabilities
+type CompletionsCapability struct{}
+
+// CompletionReference identifies what is being completed: an argument of a
+// prompt, by name, or a variable of a resource template, by URI template
+type CompletionReference struct {
+	// Type is RefTypePrompt or RefTypeResource
+	Type string `json:"type"`
+	// Name is the name of the prompt
+	Name string `json:"name,omitempty"`
+	// URI is the URI template of the resource template
+	URI string `json:"uri,omitempty"`
+}
+
+// PromptReference returns the reference of a prompt
+func PromptReference(name string) CompletionReference {
+	return CompletionReference{Type: RefTypePrompt, Name: name}
+}
+
+// ResourceReference returns the reference of a resource template
+func ResourceReference(uriTemplate string) CompletionReference {
+	return CompletionReference{Type: RefTypeResource, URI: uriTemplate}
+}
+
+// CompletionArgument is the argument being completed and its partial value
+type CompletionArgument struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// CompletionContext holds what the client already knows besides the
+// argument being completed
+type CompletionContext struct {
+	// Arguments holds the values of the arguments already resolved
+	Arguments map[string]string `json:"arguments,omitempty"`
+}
+
+// CompleteRequest represents the parameters of a completion/complete
+// request
+type CompleteRequest struct {
+	Ref      CompletionReference `json:"ref"`
+	Argument CompletionArgument  `json:"argument"`
+	// Context requires protocol version 2025-06-18
+	Context *CompletionContext `json:"context,omitempty"`
+}
+
+// Completion holds the suggested values of an argument
+type Completion struct {
+	// Values holds at most MaxCompletionValues values
+	Values []string `json:"values"`
+	// Total is the number of available values, zero when unknown
+	Total int `json:"total,omitempty"`
+	// HasMore is set when more values are available than returned
+	HasMore bool `json:"hasMore,omitempty"`
+}
+
+// CompleteResult represents the result of a completion/complete request
+type CompleteResult struct {
+	Completion Completion `json:"completion"`
+}
@@ -0,0 +1,314 @@
+package types
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ContentType represents the type of content in a message
+type ContentType string
+
+const (
+	ContentTypeText         ContentType = "text"
+	ContentTypeImage        ContentType = "image"
+	ContentTypeAudio        ContentType = "audio"
+	ContentTypeResource     ContentType = "resource"
+	ContentTypeResourceLink ContentType = "resource_link"
+)
+
+// Content is a content block of a tool result or a message. It is one of
+// TextContent, ImageContent, AudioContent, EmbeddedResource or ResourceLink,
+// or RawContent for types added by later protocol versions;
+// UnmarshalContent decodes it.
+type Content interface {
+	// ContentType returns the type of the content block
+	ContentType() ContentType
+}
+
+// Annotations tell clients how to use or display content
+type Annotations struct {
+	// Audience lists who the content is intended for: "user", "assistant"
+	// or both
+	Audience []string `json:"audience,omitempty"`
+	// Priority ranges from 0 (optional) to 1 (required)
+	Priority *float64 `json:"priority,omitempty"`
+	// LastModified is an ISO 8601 timestamp
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+// TextContent represents text content in a message
+type TextContent struct {
+	Type        ContentType  `json:"type"`
+	Text        string       `json:"text"`
+	Annotations *Annotations `json:"annotations,omitempty"`
+}
+
+// NewTextContent creates a text content block
+func NewTextContent(text string) TextContent {
+	return TextContent{Type: ContentTypeText, Text: text}
+}
+
+// ContentType implements Content
+func (TextContent) ContentType() ContentType { return ContentTypeText }
+
+// MarshalJSON sets the type of the content block
+func (c TextContent) MarshalJSON() ([]byte, error) {
+	type alias TextContent
+	c.Type = ContentTypeText
+	return json.Marshal(alias(c))
+}
+
+// ImageContent represents an image, encoded in base64
+type ImageContent struct {
+	Type        ContentType  `json:"type"`
+	Data        string       `json:"data"`
+	MimeType    string       `json:"mimeType"`
+	Annotations *Annotations `json:"annotations,omitempty"`
+}
+
+// NewImageContent creates an image content block from raw image data
+func NewImageContent(data []byte, mimeType string) ImageContent {
+	return ImageContent{Type: ContentTypeImage, Data: base64.StdEncoding.EncodeToString(data), MimeType: mimeType}
+}
+
+// ContentType implements Content
+func (ImageContent) ContentType() ContentType { return ContentTypeImage }
+
+// MarshalJSON sets the type of the content block
+func (c ImageContent) MarshalJSON() ([]byte, error) {
+	type alias ImageContent
+	c.Type = ContentTypeImage
+	return json.Marshal(alias(c))
+}
+
+// Bytes decodes the image data
+func (c ImageContent) Bytes() ([]byte, error) {
+	return base64.StdEncoding.DecodeString(c.Data)
+}
+
+// AudioContent represents audio, encoded in base64. It requires protocol
+// version 2025-03-26; servers send older clients a text block instead.
+type AudioContent struct {
+	Type        ContentType  `json:"type"`
+	Data        string       `json:"data"`
+	MimeType    string       `json:"mimeType"`
+	Annotations *Annotations `json:"annotations,omitempty"`
+}
+
+// NewAudioContent creates an audio content block from raw audio data
+func NewAudioContent(data []byte, mimeType string) AudioContent {
+	return AudioContent{Type: ContentTypeAudio, Data: base64.StdEncoding.EncodeToString(data), MimeType: mimeType}
+}
+
+// ContentType implements Content
+func (AudioContent) ContentType() ContentType { return ContentTypeAudio }
+
+// MarshalJSON sets the type of the content block
+func (c AudioContent) MarshalJSON() ([]byte, error) {
+	type alias AudioContent
+	c.Type = ContentTypeAudio
+	return json.Marshal(alias(c))
+}
+
+// Bytes decodes the audio data
+func (c AudioContent) Bytes() ([]byte, error) {
+	return base64.StdEncoding.DecodeString(c.Data)
+}
+
+// EmbeddedResource embeds the contents of a resource
+type EmbeddedResource struct {
+	Type        ContentType      `json:"type"`
+	Resource    ResourceContents `json:"resource"`
+	Annotations *Annotations     `json:"annotations,omitempty"`
+}
+
+// NewEmbeddedResource creates a content block embedding a resource
+func NewEmbeddedResource(contents ResourceContents) EmbeddedResource {
+	return EmbeddedResource{Type: ContentTypeResource, Resource: contents}
+}
+
+// ContentType implements Content
+func (EmbeddedResource) ContentType() ContentType { return ContentTypeResource }
+
+// MarshalJSON sets the type of the content block
+func (c EmbeddedResource) MarshalJSON() ([]byte, error) {
+	type alias EmbeddedResource
+	c.Type = ContentTypeResource
+	return json.Marshal(alias(c))
+}
+
+// ResourceLink points to a resource the client can read. It requires
+// protocol version 2025-06-18; servers send older clients a text block with
+// its URI instead.
+type ResourceLink struct {
+	Type        ContentType  `json:"type"`
+	URI         string       `json:"uri"`
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	MimeType    string       `json:"mimeType,omitempty"`
+	Size        *int64       `json:"size,omitempty"`
+	Annotations *Annotations `json:"annotations,omitempty"`
+}
+
+// NewResourceLink creates a content block linking to a resource
+func NewResourceLink(uri, name, mimeType string) ResourceLink {
+	return ResourceLink{Type: ContentTypeResourceLink, URI: uri, Name: name, MimeType: mimeType}
+}
+
+// ContentType implements Content
+func (ResourceLink) ContentType() ContentType { return ContentTypeResourceLink }
+
+// MarshalJSON sets the type of the content block
+func (c ResourceLink) MarshalJSON() ([]byte, error) {
+	type alias ResourceLink
+	c.Type = ContentTypeResourceLink
+	return json.Marshal(alias(c))
+}
+
+// RawContent is a content block of a type this package does not know. It
+// keeps the block as it was received, so it is passed on unchanged.
+type RawContent struct {
+	Type ContentType
+	Data json.RawMessage
+}
+
+// ContentType implements Content
+func (c RawContent) ContentType() ContentType { return c.Type }
+
+// MarshalJSON returns the block as it was received
+func (c RawContent) MarshalJSON() ([]byte, error) {
+	return c.Data, nil
+}
+
+// ResourceContent represents an embedded resource in a message
+//
+// Deprecated: use EmbeddedResource, which follows the protocol
+type ResourceContent struct {
+	Type     ContentType `json:"type"`
+	URI      string      `json:"uri"`
+	MimeType string      `json:"mimeType"`
+	Content  []byte      `json:"content,omitempty"`
+}
+
+// UnmarshalContent decodes a content block into the type matching its
+// "type" field. The returned Content holds a value, not a pointer, e.g. a
+// TextContent. Blocks of unknown types are returned as RawContent.
+func UnmarshalContent(data []byte) (Content, error) {
+	var head struct {
+		Type ContentType `json:"type"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return nil, errors.Wrap(err, "failed to decode content")
+	}
+	if head.Type == "" {
+		return nil, errors.New("content has no type")
+	}
+
+	var (
+		content Content
+		err     error
+	)
+	switch head.Type {
+	case ContentTypeText:
+		var c TextContent
+		err = json.Unmarshal(data, &c)
+		content = c
+	case ContentTypeImage:
+		var c ImageContent
+		err = json.Unmarshal(data, &c)
+		content = c
+	case ContentTypeAudio:
+		var c AudioContent
+		err = json.Unmarshal(data, &c)
+		content = c
+	case ContentTypeResource:
+		var c EmbeddedResource
+		err = json.Unmarshal(data, &c)
+		content = c
+	case ContentTypeResourceLink:
+		var c ResourceLink
+		err = json.Unmarshal(data, &c)
+		content = c
+	default:
+		return RawContent{Type: head.Type, Data: append(json.RawMessage(nil), data...)}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode %s content", head.Type)
+	}
+	return content, nil
+}
+
+// unmarshalContents decodes a list of content blocks
+func unmarshalContents(raw []json.RawMessage) ([]Content, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	contents := make([]Content, 0, len(raw))
+	for _, data := range raw {
+		content, err := UnmarshalContent(data)
+		if err != nil {
+			return nil, err
+		}
+		contents = append(contents, content)
+	}
+	return contents, nil
+}
+
+// NewTextToolResult creates a tool result holding a single text block
+func NewTextToolResult(text string) *CallToolResult {
+	return &CallToolResult{Content: []Content{NewTextContent(text)}}
+}
+
+// NewErrorToolResult creates a tool result reporting a failure of the tool
+// itself, which the model can see and react to, unlike protocol errors
+func NewErrorToolResult(message string) *CallToolResult {
+	return &CallToolResult{Content: []Content{NewTextContent(message)}, IsError: true}
+}
+
+// UnmarshalJSON decodes the content blocks into their concrete types
+func (r *CallToolResult) UnmarshalJSON(data []byte) error {
+	type alias CallToolResult
+	aux := struct {
+		*alias
+		Content []json.RawMessage `json:"content"`
+	}{alias: (*alias)(r)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	contents, err := unmarshalContents(aux.Content)
+	if err != nil {
+		return err
+	}
+	r.Content = contents
+	return nil
+}
+
+// Text returns the text blocks of the result, joined by newlines
+func (r *CallToolResult) Text() string {
+	var texts []string
+	for _, content := range r.Content {
+		switch text := content.(type) {
+		case TextContent:
+			texts = append(texts, text.Text)
+		case *TextContent:
+			texts = append(texts, text.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// DecodeStructuredContent decodes the structured content into v
+func (r *CallToolResult) DecodeStructuredContent(v interface{}) error {
+	if r.StructuredContent == nil {
+		return errors.New("result has no structured content")
+	}
+	data, err := json.Marshal(r.StructuredContent)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode structured content")
+	}
+	return json.Unmarshal(data, v)
+}
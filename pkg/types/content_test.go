@@ -0,0 +1,113 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallToolResult_JSON(t *testing.T) {
+	size := int64(12)
+	result := CallToolResult{
+		Content: []Content{
+			TextContent{Text: "hello"},
+			NewImageContent([]byte("png"), "image/png"),
+			NewAudioContent([]byte("wav"), "audio/wav"),
+			NewEmbeddedResource(NewResourceContents("file:///a.txt", []byte("a"), "text/plain")),
+			ResourceLink{URI: "file:///b.txt", Name: "b", Size: &size},
+		},
+		StructuredContent: map[string]interface{}{"ok": true},
+	}
+
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"content": [
+			{"type": "text", "text": "hello"},
+			{"type": "image", "data": "cG5n", "mimeType": "image/png"},
+			{"type": "audio", "data": "d2F2", "mimeType": "audio/wav"},
+			{"type": "resource", "resource": {"uri": "file:///a.txt", "mimeType": "text/plain", "text": "a"}},
+			{"type": "resource_link", "uri": "file:///b.txt", "name": "b", "size": 12}
+		],
+		"structuredContent": {"ok": true}
+	}`, string(data))
+
+	var decoded CallToolResult
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Len(t, decoded.Content, 5)
+	assert.Equal(t, NewTextContent("hello"), decoded.Content[0])
+	assert.Equal(t, "hello", decoded.Text())
+
+	image, ok := decoded.Content[1].(ImageContent)
+	require.True(t, ok)
+	raw, err := image.Bytes()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("png"), raw)
+
+	assert.IsType(t, AudioContent{}, decoded.Content[2])
+	resource, ok := decoded.Content[3].(EmbeddedResource)
+	require.True(t, ok)
+	assert.Equal(t, "a", resource.Resource.Text)
+	link, ok := decoded.Content[4].(ResourceLink)
+	require.True(t, ok)
+	assert.Equal(t, int64(12), *link.Size)
+	assert.Equal(t, map[string]interface{}{"ok": true}, decoded.StructuredContent)
+}
+
+func TestUnmarshalContent_Unknown(t *testing.T) {
+	var result CallToolResult
+	require.NoError(t, json.Unmarshal([]byte(`{"content":[{"type":"video","uri":"file:///a.mp4"},{"type":"text","text":"a"}]}`), &result))
+	require.Len(t, result.Content, 2)
+	assert.Equal(t, ContentType("video"), result.Content[0].ContentType())
+	assert.IsType(t, RawContent{}, result.Content[0])
+
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"content":[{"type":"video","uri":"file:///a.mp4"},{"type":"text","text":"a"}]}`, string(data))
+}
+
+func TestUnmarshalContent_Errors(t *testing.T) {
+	_, err := UnmarshalContent([]byte(`{"text":"no type"}`))
+	assert.Error(t, err)
+	_, err = UnmarshalContent([]byte(`[]`))
+	assert.Error(t, err)
+
+	var result CallToolResult
+	assert.Error(t, json.Unmarshal([]byte(`{"content":[{"type":"text","text":1}]}`), &result))
+}
+
+func TestNewErrorToolResult(t *testing.T) {
+	data, err := json.Marshal(NewErrorToolResult("disk full"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"content":[{"type":"text","text":"disk full"}],"isError":true}`, string(data))
+}
+
+func TestGetPromptResult_JSON(t *testing.T) {
+	result := GetPromptResult{
+		Description: "Review code",
+		Messages: []PromptMessage{
+			NewPromptMessage(RoleUser, NewTextContent("Review this file")),
+			NewPromptMessage(RoleUser, NewEmbeddedResource(NewResourceContents("file:///main.go", []byte("package main"), "text/x-go"))),
+			NewPromptMessage(RoleAssistant, NewImageContent([]byte("png"), "image/png")),
+		},
+	}
+
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"description": "Review code",
+		"messages": [
+			{"role": "user", "content": {"type": "text", "text": "Review this file"}},
+			{"role": "user", "content": {"type": "resource", "resource": {"uri": "file:///main.go", "mimeType": "text/x-go", "text": "package main"}}},
+			{"role": "assistant", "content": {"type": "image", "data": "cG5n", "mimeType": "image/png"}}
+		]
+	}`, string(data))
+
+	var decoded GetPromptResult
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, result, decoded)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"messages":[{"role":"user","content":{"text":"no type"}}]}`), &decoded))
+}
@@ -0,0 +1,37 @@
+package types
+
+import "encoding/json"
+
+// Actions of ElicitResult
+const (
+	// ElicitActionAccept means the user submitted the requested data
+	ElicitActionAccept = "accept"
+	// ElicitActionDecline means the user refused to provide the data
+	ElicitActionDecline = "decline"
+	// ElicitActionCancel means the user dismissed the request without
+	// choosing
+	ElicitActionCancel = "cancel"
+)
+
+// ElicitationCapability represents elicitation-related capabilities
+type ElicitationCapability struct{}
+
+// ElicitRequest represents the parameters of an elicitation/create request,
+// which asks the user for structured input
+type ElicitRequest struct {
+	// Message is shown to the user
+	Message string `json:"message"`
+	// RequestedSchema describes the requested data. It is a flat object
+	// schema whose properties are strings, numbers, integers, booleans or
+	// string enums.
+	RequestedSchema json.RawMessage `json:"requestedSchema"`
+}
+
+// ElicitResult represents the answer of the user to an elicitation request
+type ElicitResult struct {
+	// Action is one of ElicitActionAccept, ElicitActionDecline and
+	// ElicitActionCancel
+	Action string `json:"action"`
+	// Content holds the submitted data when the action is accept
+	Content map[string]interface{} `json:"content,omitempty"`
+}
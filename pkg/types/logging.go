@@ -0,0 +1,58 @@
+package types
+
+// LoggingLevel is the severity of a log message sent to clients, as defined
+// by RFC 5424. It differs from LogLevel, the level of local logs.
+type LoggingLevel string
+
+// Logging levels, from the least to the most severe
+const (
+	LoggingLevelDebug     LoggingLevel = "debug"
+	LoggingLevelInfo      LoggingLevel = "info"
+	LoggingLevelNotice    LoggingLevel = "notice"
+	LoggingLevelWarning   LoggingLevel = "warning"
+	LoggingLevelError     LoggingLevel = "error"
+	LoggingLevelCritical  LoggingLevel = "critical"
+	LoggingLevelAlert     LoggingLevel = "alert"
+	LoggingLevelEmergency LoggingLevel = "emergency"
+)
+
+// loggingSeverities orders the logging levels
+var loggingSeverities = map[LoggingLevel]int{
+	LoggingLevelDebug:     0,
+	LoggingLevelInfo:      1,
+	LoggingLevelNotice:    2,
+	LoggingLevelWarning:   3,
+	LoggingLevelError:     4,
+	LoggingLevelCritical:  5,
+	LoggingLevelAlert:     6,
+	LoggingLevelEmergency: 7,
+}
+
+// Valid reports whether the level is one of the logging levels
+func (l LoggingLevel) Valid() bool {
+	_, ok := loggingSeverities[l]
+	return ok
+}
+
+// AtLeast reports whether the level is as severe as min or more. Invalid
+// levels are never.
+func (l LoggingLevel) AtLeast(min LoggingLevel) bool {
+	severity, ok := loggingSeverities[l]
+	minSeverity, minOk := loggingSeverities[min]
+	return ok && minOk && severity >= minSeverity
+}
+
+// SetLevelRequest represents the parameters of a logging/setLevel request
+type SetLevelRequest struct {
+	// Level is the minimum level of the messages the client receives
+	Level LoggingLevel `json:"level"`
+}
+
+// LoggingMessageNotification carries a log message to the client
+type LoggingMessageNotification struct {
+	Level LoggingLevel `json:"level"`
+	// Logger optionally names the logger that emitted the message
+	Logger string `json:"logger,omitempty"`
+	// Data is the message, any JSON value
+	Data interface{} `json:"data"`
+}
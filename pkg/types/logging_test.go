@@ -0,0 +1,16 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoggingLevel(t *testing.T) {
+	assert.True(t, LoggingLevelError.AtLeast(LoggingLevelWarning))
+	assert.True(t, LoggingLevelWarning.AtLeast(LoggingLevelWarning))
+	assert.False(t, LoggingLevelNotice.AtLeast(LoggingLevelWarning))
+	assert.False(t, LoggingLevel("loud").AtLeast(LoggingLevelDebug))
+	assert.True(t, LoggingLevelEmergency.Valid())
+	assert.False(t, LoggingLevel("warn").Valid())
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/pkg/errors"
+
 	"github.com/harriteja/mcp-go-sdk/pkg/validation/core"
 )
 
@@ -72,40 +74,47 @@ type PromptArgument struct {
 	Schema      json.RawMessage `json:"schema,omitempty"`
 }
 
-// PromptMessage represents a message in a prompt
-type PromptMessage struct {
-	Role    string          `json:"role"` // "user" or "assistant"
-	Content json.RawMessage `json:"content"`
-}
-
-// ContentType represents the type of content in a message
-type ContentType string
-
+// Roles of the messages of a conversation
 const (
-	ContentTypeText     ContentType = "text"
-	ContentTypeImage    ContentType = "image"
-	ContentTypeResource ContentType = "resource"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
 )
 
-// TextContent represents text content in a message
-type TextContent struct {
-	Type ContentType `json:"type"`
-	Text string      `json:"text"`
+// PromptMessage represents a message in a prompt
+type PromptMessage struct {
+	// Role is RoleUser or RoleAssistant
+	Role string `json:"role"`
+	// Content is a TextContent, ImageContent, AudioContent,
+	// EmbeddedResource or ResourceLink
+	Content Content `json:"content"`
 }
 
-// ImageContent represents image content in a message
-type ImageContent struct {
-	Type   ContentType `json:"type"`
-	URI    string      `json:"uri"`
-	Format string      `json:"format,omitempty"`
+// NewPromptMessage creates a prompt message
+func NewPromptMessage(role string, content Content) PromptMessage {
+	return PromptMessage{Role: role, Content: content}
 }
 
-// ResourceContent represents an embedded resource in a message
-type ResourceContent struct {
-	Type     ContentType `json:"type"`
-	URI      string      `json:"uri"`
-	MimeType string      `json:"mimeType"`
-	Content  []byte      `json:"content,omitempty"`
+// UnmarshalJSON decodes the content into its concrete type
+func (m *PromptMessage) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Role    string          `json:"role"`
+		Content json.RawMessage `json:"content"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	m.Role = aux.Role
+	m.Content = nil
+	if len(aux.Content) == 0 {
+		return nil
+	}
+	content, err := UnmarshalContent(aux.Content)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode message")
+	}
+	m.Content = content
+	return nil
 }
 
 // ExtendedPrompt represents a prompt template that can be rendered with parameters
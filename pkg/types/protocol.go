@@ -0,0 +1,248 @@
+package types
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
+)
+
+// MCP request method names
+const (
+	MethodInitialize             = "initialize"
+	MethodPing                   = "ping"
+	MethodToolsList              = "tools/list"
+	MethodToolsCall              = "tools/call"
+	MethodPromptsList            = "prompts/list"
+	MethodPromptsGet             = "prompts/get"
+	MethodResourcesList          = "resources/list"
+	MethodResourcesRead          = "resources/read"
+	MethodResourcesTemplatesList = "resources/templates/list"
+	MethodResourcesSubscribe     = "resources/subscribe"
+	MethodResourcesUnsubscribe   = "resources/unsubscribe"
+	MethodCompletionComplete     = "completion/complete"
+	MethodLoggingSetLevel        = "logging/setLevel"
+
+	// Requests sent by the server to the client
+	MethodSamplingCreateMessage = "sampling/createMessage"
+	MethodRootsList             = "roots/list"
+	MethodElicitationCreate     = "elicitation/create"
+)
+
+// MCP notification method names
+const (
+	NotificationInitialized = "notifications/initialized"
+	NotificationCancelled   = "notifications/cancelled"
+	NotificationProgress    = "notifications/progress"
+	NotificationMessage     = "notifications/message"
+
+	NotificationToolsListChanged     = "notifications/tools/list_changed"
+	NotificationResourcesListChanged = "notifications/resources/list_changed"
+	NotificationResourceUpdated      = "notifications/resources/updated"
+	NotificationRootsListChanged     = "notifications/roots/list_changed"
+)
+
+// CodeResourceNotFound is the error code of reads of resources that do not
+// exist
+const CodeResourceNotFound = -32002
+
+// CancelledNotification is sent by either side to cancel a request it issued
+// earlier
+type CancelledNotification struct {
+	// RequestID is the ID of the request to cancel
+	RequestID jsonrpc.ID `json:"requestId"`
+	// Reason optionally describes why the request was cancelled
+	Reason string `json:"reason,omitempty"`
+}
+
+// RequestMeta holds the _meta member of request params
+type RequestMeta struct {
+	// ProgressToken asks the receiver to report the progress of the request
+	// with notifications/progress carrying the token
+	ProgressToken *jsonrpc.ID `json:"progressToken,omitempty"`
+}
+
+// ProgressNotification reports the progress of a long running request
+type ProgressNotification struct {
+	// ProgressToken is the token of the request the progress belongs to
+	ProgressToken jsonrpc.ID `json:"progressToken"`
+	// Progress increases with every notification
+	Progress float64 `json:"progress"`
+	// Total is the value progress reaches on completion, zero when unknown
+	Total float64 `json:"total,omitempty"`
+	// Message describes the current progress. It requires protocol version
+	// 2025-03-26.
+	Message string `json:"message,omitempty"`
+}
+
+// PaginatedRequest represents the parameters of list requests
+type PaginatedRequest struct {
+	// Cursor is the nextCursor of the previous page, empty for the first
+	// page
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// ListToolsResult represents the result of a tools/list request
+type ListToolsResult struct {
+	Tools []Tool `json:"tools"`
+	// NextCursor is set when more tools are available
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// CallToolRequest represents the parameters of a tools/call request
+type CallToolRequest struct {
+	Name      string                 `json:"name"`
+	Arguments map[string]interface{} `json:"arguments,omitempty"`
+	Meta      *RequestMeta           `json:"_meta,omitempty"`
+}
+
+// CallToolResult represents the result of a tools/call request
+type CallToolResult struct {
+	// Content holds the unstructured result, for the model
+	Content []Content `json:"content"`
+	// StructuredContent holds the result as a JSON object, matching the
+	// output schema of the tool if it has one
+	StructuredContent interface{} `json:"structuredContent,omitempty"`
+	// IsError reports that the tool failed. The error is described by
+	// Content.
+	IsError bool `json:"isError,omitempty"`
+}
+
+// ListPromptsResult represents the result of a prompts/list request
+type ListPromptsResult struct {
+	Prompts []Prompt `json:"prompts"`
+	// NextCursor is set when more prompts are available
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// GetPromptRequest represents the parameters of a prompts/get request
+type GetPromptRequest struct {
+	Name      string                 `json:"name"`
+	Arguments map[string]interface{} `json:"arguments,omitempty"`
+}
+
+// GetPromptResult represents the result of a prompts/get request: the
+// prompt rendered as a conversation
+type GetPromptResult struct {
+	Description string          `json:"description,omitempty"`
+	Messages    []PromptMessage `json:"messages"`
+}
+
+// ListResourcesResult represents the result of a resources/list request
+type ListResourcesResult struct {
+	Resources []Resource `json:"resources"`
+	// NextCursor is set when more resources are available
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// ReadResourceRequest represents the parameters of a resources/read request
+type ReadResourceRequest struct {
+	URI string `json:"uri"`
+}
+
+// SubscribeRequest represents the parameters of a resources/subscribe
+// request
+type SubscribeRequest struct {
+	URI string `json:"uri"`
+}
+
+// UnsubscribeRequest represents the parameters of a resources/unsubscribe
+// request
+type UnsubscribeRequest struct {
+	URI string `json:"uri"`
+}
+
+// ResourceUpdatedNotification tells a subscribed client that a resource
+// changed and may be read again
+type ResourceUpdatedNotification struct {
+	URI string `json:"uri"`
+}
+
+// ReadResourceResult represents the result of a resources/read request
+type ReadResourceResult struct {
+	Contents []ResourceContents `json:"contents"`
+}
+
+// ListResourceTemplatesResult represents the result of a
+// resources/templates/list request
+type ListResourceTemplatesResult struct {
+	ResourceTemplates []ResourceTemplate `json:"resourceTemplates"`
+	// NextCursor is set when more templates are available
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// ResourceContents holds the contents of a resource. Textual data is carried
+// in Text, anything else base64 encoded in Blob.
+type ResourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text,omitempty"`
+	Blob     string `json:"blob,omitempty"`
+}
+
+// NewResourceContents creates resource contents, choosing between text and
+// blob encoding based on the MIME type
+func NewResourceContents(uri string, data []byte, mimeType string) ResourceContents {
+	contents := ResourceContents{
+		URI:      uri,
+		MimeType: mimeType,
+	}
+	if IsTextMimeType(mimeType) {
+		contents.Text = string(data)
+	} else {
+		contents.Blob = base64.StdEncoding.EncodeToString(data)
+	}
+	return contents
+}
+
+// MarshalJSON always emits one of text and blob, even when empty. Contents
+// with neither set are text or blob depending on their MIME type, like
+// NewResourceContents chooses.
+func (c ResourceContents) MarshalJSON() ([]byte, error) {
+	type text struct {
+		URI      string `json:"uri"`
+		MimeType string `json:"mimeType,omitempty"`
+		Text     string `json:"text"`
+	}
+	type blob struct {
+		URI      string `json:"uri"`
+		MimeType string `json:"mimeType,omitempty"`
+		Blob     string `json:"blob"`
+	}
+
+	if c.Blob != "" || (c.Text == "" && !IsTextMimeType(c.MimeType)) {
+		return json.Marshal(blob{URI: c.URI, MimeType: c.MimeType, Blob: c.Blob})
+	}
+	return json.Marshal(text{URI: c.URI, MimeType: c.MimeType, Text: c.Text})
+}
+
+// Bytes returns the raw resource data
+func (c ResourceContents) Bytes() ([]byte, error) {
+	if c.Blob == "" {
+		return []byte(c.Text), nil
+	}
+	data, err := base64.StdEncoding.DecodeString(c.Blob)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid blob encoding")
+	}
+	return data, nil
+}
+
+// IsTextMimeType reports whether data of the given MIME type is text
+func IsTextMimeType(mimeType string) bool {
+	mimeType = strings.ToLower(strings.TrimSpace(strings.SplitN(mimeType, ";", 2)[0]))
+	switch {
+	case strings.HasPrefix(mimeType, "text/"):
+		return true
+	case strings.HasSuffix(mimeType, "+json"), strings.HasSuffix(mimeType, "+xml"):
+		return true
+	}
+	switch mimeType {
+	case "application/json", "application/xml", "application/javascript", "application/yaml", "application/x-yaml":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
+)
+
+func TestResourceContents(t *testing.T) {
+	text := NewResourceContents("file:///a.txt", []byte("hello"), "text/plain; charset=utf-8")
+	assert.Equal(t, "hello", text.Text)
+	assert.Empty(t, text.Blob)
+
+	blob := NewResourceContents("file:///a.bin", []byte{0xff, 0x00}, "application/octet-stream")
+	assert.Empty(t, blob.Text)
+	assert.Equal(t, "/wA=", blob.Blob)
+
+	for _, contents := range []ResourceContents{text, blob} {
+		data, err := json.Marshal(contents)
+		require.NoError(t, err)
+
+		var decoded ResourceContents
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, contents, decoded)
+	}
+
+	data, err := blob.Bytes()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0xff, 0x00}, data)
+
+	assert.True(t, IsTextMimeType("application/ld+json"))
+	assert.False(t, IsTextMimeType("image/png"))
+}
+
+func TestResourceContents_Empty(t *testing.T) {
+	tests := map[string]ResourceContents{
+		`{"uri":"file:///a.txt","mimeType":"text/plain","text":""}`:               NewResourceContents("file:///a.txt", nil, "text/plain"),
+		`{"uri":"file:///a.bin","mimeType":"application/octet-stream","blob":""}`: NewResourceContents("file:///a.bin", nil, "application/octet-stream"),
+		`{"uri":"file:///a","blob":""}`:                                           {URI: "file:///a"},
+	}
+	for want, contents := range tests {
+		data, err := json.Marshal(contents)
+		require.NoError(t, err)
+		assert.JSONEq(t, want, string(data))
+	}
+}
+
+func TestError_JSONRPCRoundTrip(t *testing.T) {
+	mcpErr := NewError(404, "Tool not found")
+	mcpErr.Data = map[string]interface{}{"name": "missing"}
+
+	obj := jsonrpc.ErrorFrom(mcpErr)
+	assert.Equal(t, 404, obj.Code)
+	assert.Equal(t, "Tool not found", obj.Message)
+
+	data, err := json.Marshal(obj)
+	require.NoError(t, err)
+	var decoded jsonrpc.ErrorObject
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	converted := ErrorFromJSONRPC(&decoded)
+	assert.Equal(t, mcpErr.Code, converted.Code)
+	assert.Equal(t, mcpErr.Message, converted.Message)
+	assert.Equal(t, "missing", converted.Data["name"])
+}
@@ -0,0 +1,17 @@
+package types
+
+// Root is a directory or file the client grants the server access to
+type Root struct {
+	// URI identifies the root. It must be a file:// URI.
+	URI string `json:"uri"`
+	// Name optionally names the root for display
+	Name string `json:"name,omitempty"`
+}
+
+// ListRootsResult represents the result of a roots/list request
+type ListRootsResult struct {
+	Roots []Root `json:"roots"`
+}
+
+// RootsListChangedNotification is sent by the client when its roots change
+type RootsListChangedNotification struct{}
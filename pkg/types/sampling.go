@@ -0,0 +1,121 @@
+package types
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// Values of CreateMessageRequest.IncludeContext
+const (
+	IncludeContextNone       = "none"
+	IncludeContextThisServer = "thisServer"
+	IncludeContextAllServers = "allServers"
+)
+
+// Common values of CreateMessageResponse.StopReason
+const (
+	StopReasonEndTurn      = "endTurn"
+	StopReasonStopSequence = "stopSequence"
+	StopReasonMaxTokens    = "maxTokens"
+)
+
+// SamplingMessage is a message of the conversation a server asks the client
+// to continue
+type SamplingMessage struct {
+	// Role is "user" or "assistant"
+	Role string `json:"role"`
+	// Content is a TextContent, ImageContent or AudioContent
+	Content Content `json:"content"`
+}
+
+// UnmarshalJSON decodes the content into its concrete type
+func (m *SamplingMessage) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Role    string          `json:"role"`
+		Content json.RawMessage `json:"content"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	m.Role = aux.Role
+	m.Content = nil
+	if len(aux.Content) == 0 {
+		return nil
+	}
+	content, err := UnmarshalContent(aux.Content)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode message")
+	}
+	m.Content = content
+	return nil
+}
+
+// ModelHint suggests a model by name, or by a substring of its name such as
+// a model family
+type ModelHint struct {
+	Name string `json:"name,omitempty"`
+}
+
+// ModelPreferences tells the client how to pick the model used for
+// sampling. Priorities range from 0 to 1.
+type ModelPreferences struct {
+	// Hints are considered in order
+	Hints                []ModelHint `json:"hints,omitempty"`
+	CostPriority         *float64    `json:"costPriority,omitempty"`
+	SpeedPriority        *float64    `json:"speedPriority,omitempty"`
+	IntelligencePriority *float64    `json:"intelligencePriority,omitempty"`
+}
+
+// CreateMessageRequest represents the parameters of a
+// sampling/createMessage request, which asks the client to sample a language
+// model
+type CreateMessageRequest struct {
+	Messages         []SamplingMessage `json:"messages"`
+	ModelPreferences *ModelPreferences `json:"modelPreferences,omitempty"`
+	SystemPrompt     string            `json:"systemPrompt,omitempty"`
+	// IncludeContext asks the client to include context from MCP servers,
+	// see the IncludeContext constants
+	IncludeContext string                 `json:"includeContext,omitempty"`
+	Temperature    *float64               `json:"temperature,omitempty"`
+	MaxTokens      int                    `json:"maxTokens"`
+	StopSequences  []string               `json:"stopSequences,omitempty"`
+	Metadata       map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// CreateMessageResponse represents the result of a sampling/createMessage
+// request
+type CreateMessageResponse struct {
+	// Role is the role of the sampled message, usually "assistant"
+	Role string `json:"role"`
+	// Content is a TextContent, ImageContent or AudioContent
+	Content Content `json:"content"`
+	// Model is the name of the model that was sampled
+	Model string `json:"model"`
+	// StopReason tells why sampling stopped, see the StopReason constants
+	StopReason string `json:"stopReason,omitempty"`
+}
+
+// UnmarshalJSON decodes the content into its concrete type
+func (r *CreateMessageResponse) UnmarshalJSON(data []byte) error {
+	type alias CreateMessageResponse
+	aux := struct {
+		*alias
+		Content json.RawMessage `json:"content"`
+	}{alias: (*alias)(r)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	r.Content = nil
+	if len(aux.Content) == 0 {
+		return nil
+	}
+	content, err := UnmarshalContent(aux.Content)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode result")
+	}
+	r.Content = content
+	return nil
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"time"
+
+	"github.com/harriteja/mcp-go-sdk/pkg/jsonrpc"
 )
 
 // Implementation represents server or client implementation information
@@ -16,6 +18,7 @@ type Implementation struct {
 type ClientCapabilities struct {
 	Roots        *RootsCapability       `json:"roots,omitempty"`
 	Sampling     *SamplingCapability    `json:"sampling,omitempty"`
+	Elicitation  *ElicitationCapability `json:"elicitation,omitempty"`
 	Experimental map[string]interface{} `json:"experimental,omitempty"`
 }
 
@@ -25,6 +28,7 @@ type ServerCapabilities struct {
 	Resources    *ResourcesCapability   `json:"resources,omitempty"`
 	Tools        *ToolsCapability       `json:"tools,omitempty"`
 	Logging      *LoggingCapability     `json:"logging,omitempty"`
+	Completions  *CompletionsCapability `json:"completions,omitempty"`
 	Experimental map[string]interface{} `json:"experimental,omitempty"`
 }
 
@@ -98,6 +102,26 @@ func (e Error) Error() string {
 	return fmt.Sprintf("MCP error %d: %s", e.Code, e.Message)
 }
 
+// JSONRPCError implements jsonrpc.Coder so the error keeps its code, message
+// and data on the wire
+func (e *Error) JSONRPCError() *jsonrpc.ErrorObject {
+	obj := jsonrpc.NewError(e.Code, e.Message)
+	if e.Data != nil {
+		obj.Data = e.Data
+	}
+	return obj
+}
+
+// ErrorFromJSONRPC converts a JSON-RPC error object received from a peer into
+// an MCP error
+func ErrorFromJSONRPC(obj *jsonrpc.ErrorObject) *Error {
+	mcpErr := NewError(obj.Code, obj.Message)
+	if data, ok := obj.Data.(map[string]interface{}); ok {
+		mcpErr.Data = data
+	}
+	return mcpErr
+}
+
 // IsError checks if an error is an MCP error
 func IsError(err error) (*Error, bool) {
 	if err == nil {
@@ -123,11 +147,12 @@ type Parameters struct {
 
 // Tool represents an MCP tool
 type Tool struct {
-	Name        string                 `json:"name"`
-	Description string                 `json:"description"`
-	Parameters  *Parameters            `json:"parameters,omitempty"`
-	InputSchema json.RawMessage        `json:"inputSchema,omitempty"`
-	Annotations map[string]interface{} `json:"annotations,omitempty"`
+	Name         string                 `json:"name"`
+	Description  string                 `json:"description"`
+	Parameters   *Parameters            `json:"parameters,omitempty"`
+	InputSchema  json.RawMessage        `json:"inputSchema,omitempty"`
+	OutputSchema json.RawMessage        `json:"outputSchema,omitempty"`
+	Annotations  map[string]interface{} `json:"annotations,omitempty"`
 }
 
 // Resource represents an MCP resource
@@ -143,13 +168,17 @@ type ResourceTemplate struct {
 	URITemplate string `json:"uriTemplate"`
 	Name        string `json:"name"`
 	Description string `json:"description,omitempty"`
+	// MimeType is the type of all the resources matching the template, if
+	// they share one
+	MimeType string `json:"mimeType,omitempty"`
 }
 
-// Prompt represents an MCP prompt
+// Prompt represents an MCP prompt, as listed by prompts/list
 type Prompt struct {
-	Name        string                 `json:"name"`
-	Description string                 `json:"description"`
-	Parameters  map[string]interface{} `json:"parameters,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Arguments are the arguments the prompt is rendered with
+	Arguments []PromptArgument `json:"arguments,omitempty"`
 }
 
 // Message represents a message in the MCP protocol
@@ -159,31 +188,6 @@ type Message struct {
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
 
-// ModelPreferences represents preferences for model selection
-type ModelPreferences struct {
-	Model      string                 `json:"model,omitempty"`
-	Provider   string                 `json:"provider,omitempty"`
-	Parameters map[string]interface{} `json:"parameters,omitempty"`
-}
-
-// CreateMessageRequest represents a request to create a message
-type CreateMessageRequest struct {
-	Messages         []Message              `json:"messages"`
-	ModelPreferences *ModelPreferences      `json:"modelPreferences,omitempty"`
-	SystemPrompt     string                 `json:"systemPrompt,omitempty"`
-	Temperature      float64                `json:"temperature,omitempty"`
-	MaxTokens        int                    `json:"maxTokens"`
-	StopSequences    []string               `json:"stopSequences,omitempty"`
-	Metadata         map[string]interface{} `json:"metadata,omitempty"`
-}
-
-// CreateMessageResponse represents a response to create message request
-type CreateMessageResponse struct {
-	Message    Message                `json:"message"`
-	StopReason string                 `json:"stopReason,omitempty"`
-	Usage      map[string]interface{} `json:"usage,omitempty"`
-}
-
 // Session represents an MCP session
 type Session struct {
 	ID        string    `json:"id"`
@@ -212,22 +216,9 @@ type InitializedNotification struct {
 	// It's a notification to confirm successful initialization
 }
 
-// PingRequest represents a ping request in MCP
-type PingRequest struct {
-	// Optional timestamp for round-trip measurements
-	Timestamp int64 `json:"timestamp,omitempty"`
-}
+// PingRequest represents the parameters of a ping request, which either
+// side sends to check that the other is alive. It has none.
+type PingRequest struct{}
 
-// PingResponse represents a ping response in MCP
-type PingResponse struct {
-	// Echo back the timestamp from the request
-	Timestamp int64 `json:"timestamp,omitempty"`
-	// Server's timestamp at the time of processing
-	ServerTimestamp int64 `json:"serverTimestamp"`
-}
-
-// CancelRequest represents a request to cancel an ongoing operation
-type CancelRequest struct {
-	// ID of the request to cancel
-	ID string `json:"id"`
-}
+// PingResponse represents the result of a ping request, which is empty
+type PingResponse struct{}
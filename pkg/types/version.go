@@ -0,0 +1,86 @@
+package types
+
+// Protocol revisions known to the SDK
+const (
+	ProtocolVersion20241105 = "2024-11-05"
+	ProtocolVersion20250326 = "2025-03-26"
+	ProtocolVersion20250618 = "2025-06-18"
+)
+
+// LatestProtocolVersion is the newest protocol revision the SDK speaks
+const LatestProtocolVersion = ProtocolVersion20250618
+
+// SupportedProtocolVersions lists the protocol revisions the SDK speaks,
+// newest first
+var SupportedProtocolVersions = []string{
+	ProtocolVersion20250618,
+	ProtocolVersion20250326,
+	ProtocolVersion20241105,
+}
+
+// Feature identifies a protocol feature that only exists from a given
+// protocol revision on
+type Feature string
+
+// Version dependent protocol features
+const (
+	// FeatureToolAnnotations covers tool annotations
+	FeatureToolAnnotations Feature = "toolAnnotations"
+	// FeatureAudioContent covers audio content blocks
+	FeatureAudioContent Feature = "audioContent"
+	// FeatureCompletions covers completion/complete
+	FeatureCompletions Feature = "completions"
+	// FeatureProgressMessage covers the message field of progress notifications
+	FeatureProgressMessage Feature = "progressMessage"
+	// FeatureStructuredContent covers structured tool output and output schemas
+	FeatureStructuredContent Feature = "structuredContent"
+	// FeatureResourceLinks covers resource links in tool results
+	FeatureResourceLinks Feature = "resourceLinks"
+	// FeatureElicitation covers elicitation/create
+	FeatureElicitation Feature = "elicitation"
+)
+
+// featureVersions maps every feature to the revision that introduced it
+var featureVersions = map[Feature]string{
+	FeatureToolAnnotations:   ProtocolVersion20250326,
+	FeatureAudioContent:      ProtocolVersion20250326,
+	FeatureCompletions:       ProtocolVersion20250326,
+	FeatureProgressMessage:   ProtocolVersion20250326,
+	FeatureStructuredContent: ProtocolVersion20250618,
+	FeatureResourceLinks:     ProtocolVersion20250618,
+	FeatureElicitation:       ProtocolVersion20250618,
+}
+
+// SupportsFeature reports whether the protocol revision includes the feature.
+// Revisions are dates, so they compare in chronological order.
+func SupportsFeature(version string, feature Feature) bool {
+	since, ok := featureVersions[feature]
+	return ok && version >= since
+}
+
+// IsSupportedProtocolVersion reports whether version is one of supported
+func IsSupportedProtocolVersion(version string, supported []string) bool {
+	for _, v := range supported {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// NegotiateProtocolVersion picks the revision to use for a session: the one
+// requested by the client if it is supported, otherwise the newest supported
+// one
+func NegotiateProtocolVersion(requested string, supported []string) string {
+	if IsSupportedProtocolVersion(requested, supported) {
+		return requested
+	}
+
+	latest := ""
+	for _, v := range supported {
+		if v > latest {
+			latest = v
+		}
+	}
+	return latest
+}
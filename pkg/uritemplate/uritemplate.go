@@ -0,0 +1,368 @@
+// Package uritemplate implements RFC 6570 URI templates up to level 3, so
+// URIs can be built from templates and routed back to them with their
+// variables extracted.
+package uritemplate
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// operator describes how the variables of an expression are expanded
+type operator struct {
+	// prefix comes before the first defined variable and sep between the
+	// following ones
+	prefix, sep string
+	// named expressions expand to name=value pairs
+	named bool
+	// ifEmpty follows the name of named variables with an empty value
+	ifEmpty string
+	// reserved variables may hold reserved characters as they are
+	reserved bool
+}
+
+// operators are the operators of levels 1 to 3, by their character
+var operators = map[byte]operator{
+	'+': {sep: ",", reserved: true},
+	'#': {prefix: "#", sep: ",", reserved: true},
+	'.': {prefix: ".", sep: "."},
+	'/': {prefix: "/", sep: "/"},
+	';': {prefix: ";", sep: ";", named: true},
+	'?': {prefix: "?", sep: "&", named: true, ifEmpty: "="},
+	'&': {prefix: "&", sep: "&", named: true, ifEmpty: "="},
+}
+
+// simple is the operator of expressions without an operator character
+var simple = operator{sep: ","}
+
+const (
+	unreserved = `A-Za-z0-9\-._~`
+	reserved   = `:/?#\[\]@!$&'()*+,;=`
+	pctEncoded = `%[0-9A-Fa-f]{2}`
+)
+
+var varNameRE = regexp.MustCompile(`^(?:[A-Za-z0-9_]|%[0-9A-Fa-f]{2})(?:\.?(?:[A-Za-z0-9_]|%[0-9A-Fa-f]{2}))*$`)
+
+// expression is a {...} part of a template
+type expression struct {
+	op    operator
+	names []string
+}
+
+// part is either a literal or an expression
+type part struct {
+	literal string
+	expr    *expression
+}
+
+// Template is a compiled URI template. It is safe for concurrent use.
+type Template struct {
+	raw   string
+	parts []part
+	names []string
+
+	re *regexp.Regexp
+	// groups maps each variable to its capture groups in re, since
+	// variables of named expressions can be captured by several
+	// alternatives
+	groups map[string][]int
+	// optionalEq are the groups capturing "=value" rather than value, for
+	// the variables of ; expressions, which omit the = of empty values
+	optionalEq map[int]bool
+	// literals is the number of literal characters, see Specificity
+	literals int
+}
+
+// Parse compiles a template. Level 4 modifiers, prefixes like {var:3} and
+// explosion like {var*}, are not supported.
+func Parse(template string) (*Template, error) {
+	t := &Template{raw: template, groups: make(map[string][]int), optionalEq: make(map[int]bool)}
+
+	seen := make(map[string]bool)
+	rest := template
+	for rest != "" {
+		start := strings.IndexAny(rest, "{}")
+		if start < 0 {
+			t.addLiteral(rest)
+			break
+		}
+		if rest[start] == '}' {
+			return nil, errors.Errorf("unexpected } in template %q", template)
+		}
+		t.addLiteral(rest[:start])
+
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return nil, errors.Errorf("unterminated expression in template %q", template)
+		}
+		expr, err := parseExpression(rest[start+1 : start+end])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid template %q", template)
+		}
+		for _, name := range expr.names {
+			if seen[name] {
+				return nil, errors.Errorf("variable %s appears twice in template %q", name, template)
+			}
+			seen[name] = true
+			t.names = append(t.names, name)
+		}
+		t.parts = append(t.parts, part{expr: expr})
+		rest = rest[start+end+1:]
+	}
+
+	if err := t.compile(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// MustParse is like Parse but panics if the template is invalid
+func MustParse(template string) *Template {
+	t, err := Parse(template)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func (t *Template) addLiteral(literal string) {
+	if literal == "" {
+		return
+	}
+	t.parts = append(t.parts, part{literal: literal})
+	t.literals += len(literal)
+}
+
+// parseExpression parses the inside of an expression
+func parseExpression(s string) (*expression, error) {
+	if s == "" {
+		return nil, errors.New("empty expression")
+	}
+
+	expr := &expression{op: simple}
+	if op, ok := operators[s[0]]; ok {
+		expr.op = op
+		s = s[1:]
+	} else if strings.ContainsRune("=,!@|", rune(s[0])) {
+		return nil, errors.Errorf("reserved operator %c", s[0])
+	}
+
+	for _, name := range strings.Split(s, ",") {
+		if strings.HasSuffix(name, "*") || strings.Contains(name, ":") {
+			return nil, errors.Errorf("modifiers of variable %s are not supported", name)
+		}
+		if !varNameRE.MatchString(name) {
+			return nil, errors.Errorf("invalid variable name %q", name)
+		}
+		expr.names = append(expr.names, name)
+	}
+	return expr, nil
+}
+
+// String returns the template as it was parsed
+func (t *Template) String() string {
+	return t.raw
+}
+
+// Names returns the names of the variables, in the order they appear
+func (t *Template) Names() []string {
+	return append([]string(nil), t.names...)
+}
+
+// Specificity is the number of literal characters of the template. When
+// several templates match a URI, the one with the most literal characters
+// is usually the most specific.
+func (t *Template) Specificity() int {
+	return t.literals
+}
+
+// Expand builds a URI from the template. Variables missing from vars are
+// undefined and expand to nothing.
+func (t *Template) Expand(vars map[string]string) string {
+	var b strings.Builder
+	for _, p := range t.parts {
+		if p.expr == nil {
+			b.WriteString(p.literal)
+			continue
+		}
+
+		op := p.expr.op
+		first := true
+		for _, name := range p.expr.names {
+			value, ok := vars[name]
+			if !ok {
+				continue
+			}
+			if first {
+				b.WriteString(op.prefix)
+				first = false
+			} else {
+				b.WriteString(op.sep)
+			}
+			if op.named {
+				b.WriteString(name)
+				if value == "" {
+					b.WriteString(op.ifEmpty)
+					continue
+				}
+				b.WriteByte('=')
+			}
+			b.WriteString(encode(value, op.reserved))
+		}
+	}
+	return b.String()
+}
+
+// encode percent-encodes the characters of value that may not appear as
+// they are
+func encode(value string, allowReserved bool) string {
+	const hex = "0123456789ABCDEF"
+
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case isUnreserved(c):
+			b.WriteByte(c)
+		case allowReserved && strings.IndexByte(":/?#[]@!$&'()*+,;=", c) >= 0:
+			b.WriteByte(c)
+		case allowReserved && c == '%' && i+2 < len(value) && isHex(value[i+1]) && isHex(value[i+2]):
+			// Reserved expansion keeps percent-encoded triplets
+			b.WriteByte(c)
+		default:
+			b.WriteByte('%')
+			b.WriteByte(hex[c>>4])
+			b.WriteByte(hex[c&0x0f])
+		}
+	}
+	return b.String()
+}
+
+func isUnreserved(c byte) bool {
+	return 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || strings.IndexByte("-._~", c) >= 0
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'A' <= c && c <= 'F' || 'a' <= c && c <= 'f'
+}
+
+// Match reports whether uri is an expansion of the template and returns
+// the values of the variables it defines, decoded. Variables are matched in
+// the order they appear, so an expression that would expand only its
+// second variable assigns the value to the first one.
+func (t *Template) Match(uri string) (map[string]string, bool) {
+	m := t.re.FindStringSubmatchIndex(uri)
+	if m == nil {
+		return nil, false
+	}
+
+	vars := make(map[string]string)
+	for name, groups := range t.groups {
+		for _, g := range groups {
+			if m[2*g] < 0 {
+				continue
+			}
+			raw := uri[m[2*g]:m[2*g+1]]
+			if t.optionalEq[g] {
+				raw = strings.TrimPrefix(raw, "=")
+			}
+			value, err := url.PathUnescape(raw)
+			if err != nil {
+				return nil, false
+			}
+			vars[name] = value
+			break
+		}
+	}
+	return vars, true
+}
+
+// compile builds the regular expression matching the expansions of the
+// template
+func (t *Template) compile() error {
+	var b strings.Builder
+	group := 0
+	capture := func(name, chars string) string {
+		group++
+		t.groups[name] = append(t.groups[name], group)
+		return "(" + chars + ")"
+	}
+	// namedValue returns the pattern of a name=value pair
+	namedValue := func(op operator, name, chars string) string {
+		if op.ifEmpty != "" {
+			return regexp.QuoteMeta(name) + "=" + capture(name, chars)
+		}
+		value := capture(name, "(?:="+chars+")?")
+		t.optionalEq[group] = true
+		return regexp.QuoteMeta(name) + value
+	}
+
+	b.WriteString("^")
+	for _, p := range t.parts {
+		if p.expr == nil {
+			b.WriteString(regexp.QuoteMeta(p.literal))
+			continue
+		}
+
+		op := p.expr.op
+		chars := valueChars(op)
+		names := p.expr.names
+		if op.named {
+			// Any variable may be the first defined one, which follows
+			// the prefix
+			alternatives := make([]string, len(names))
+			for i := range names {
+				var alt strings.Builder
+				alt.WriteString(namedValue(op, names[i], chars))
+				for _, name := range names[i+1:] {
+					alt.WriteString("(?:" + regexp.QuoteMeta(op.sep) + namedValue(op, name, chars) + ")?")
+				}
+				alternatives[i] = alt.String()
+			}
+			b.WriteString("(?:" + regexp.QuoteMeta(op.prefix) + "(?:" + strings.Join(alternatives, "|") + "))?")
+			continue
+		}
+
+		var expr strings.Builder
+		expr.WriteString(regexp.QuoteMeta(op.prefix) + capture(names[0], chars))
+		for _, name := range names[1:] {
+			expr.WriteString("(?:" + regexp.QuoteMeta(op.sep) + capture(name, chars) + ")?")
+		}
+		if op.prefix != "" {
+			b.WriteString("(?:" + expr.String() + ")?")
+		} else {
+			b.WriteString(expr.String())
+		}
+	}
+	b.WriteString("$")
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return errors.Wrapf(err, "failed to compile template %q", t.raw)
+	}
+	t.re = re
+	return nil
+}
+
+// valueChars returns the pattern of the values of an operator. Separators
+// that are unreserved characters are excluded, or values could never be
+// told apart. Values are matched lazily, so that in {name}{.format} the
+// extension goes to format. Only named expressions match empty values,
+// which they spell out as name= or name; otherwise users://{id} would match
+// users:// and hide it from the handlers serving other URIs.
+func valueChars(op operator) string {
+	chars := unreserved
+	if op.reserved {
+		chars += reserved
+	}
+	if op.sep == "." {
+		chars = strings.Replace(chars, ".", "", 1)
+	}
+	if op.named {
+		return "(?:[" + chars + "]|" + pctEncoded + ")*?"
+	}
+	return "(?:[" + chars + "]|" + pctEncoded + ")+?"
+}
@@ -0,0 +1,117 @@
+package uritemplate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplate_Expand(t *testing.T) {
+	// Examples of RFC 6570, section 1.2
+	vars := map[string]string{
+		"var":   "value",
+		"hello": "Hello World!",
+		"path":  "/foo/bar",
+		"empty": "",
+		"x":     "1024",
+		"y":     "768",
+	}
+	tests := map[string]string{
+		"{var}":            "value",
+		"{hello}":          "Hello%20World%21",
+		"{+hello}":         "Hello%20World!",
+		"{+path}/here":     "/foo/bar/here",
+		"{#path,x}/here":   "#/foo/bar,1024/here",
+		"map?{x,y}":        "map?1024,768",
+		"X{.var}":          "X.value",
+		"{/var,x}/here":    "/value/1024/here",
+		"{;x,y,empty}":     ";x=1024;y=768;empty",
+		"{?x,y,empty}":     "?x=1024&y=768&empty=",
+		"?fixed=yes{&x}":   "?fixed=yes&x=1024",
+		"{?x,undef}":       "?x=1024",
+		"{/undef}":         "",
+		"file:///{var}.md": "file:///value.md",
+	}
+	for template, want := range tests {
+		assert.Equal(t, want, MustParse(template).Expand(vars), template)
+	}
+}
+
+func TestTemplate_Match(t *testing.T) {
+	tests := []struct {
+		template string
+		uri      string
+		vars     map[string]string
+	}{
+		{"file:///logs/{date}/{level}", "file:///logs/2024-01-02/error", map[string]string{"date": "2024-01-02", "level": "error"}},
+		{"file:///docs/{name}", "file:///docs/Hello%20World%21", map[string]string{"name": "Hello World!"}},
+		{"file://{+path}", "file:///etc/hosts", map[string]string{"path": "/etc/hosts"}},
+		{"repo://{owner}{/repo,branch}", "repo://golang/go/master", map[string]string{"owner": "golang", "repo": "go", "branch": "master"}},
+		{"repo://{owner}{/repo,branch}", "repo://golang/go", map[string]string{"owner": "golang", "repo": "go"}},
+		{"search://items{?q,page}", "search://items?q=go&page=2", map[string]string{"q": "go", "page": "2"}},
+		{"search://items{?q,page}", "search://items?page=2", map[string]string{"page": "2"}},
+		{"search://items{?q,page}", "search://items", map[string]string{}},
+		{"img://{name}{.format}", "img://logo.png", map[string]string{"name": "logo", "format": "png"}},
+		{"m://x{;w,h}", "m://x;w=2;h", map[string]string{"w": "2", "h": ""}},
+	}
+	for _, tt := range tests {
+		vars, ok := MustParse(tt.template).Match(tt.uri)
+		require.True(t, ok, "%s should match %s", tt.template, tt.uri)
+		assert.Equal(t, tt.vars, vars, tt.template)
+	}
+
+	misses := []struct {
+		template string
+		uri      string
+	}{
+		{"file:///logs/{date}/{level}", "file:///logs/2024/01/error"},
+		{"file:///docs/{name}", "file:///other/a"},
+		{"search://items{?q}", "search://items?other=1"},
+		{"repo://{owner}", "repo://a/b"},
+		// Variables outside named expressions are never empty
+		{"users://{id}", "users://"},
+		{"file:///logs/{date}/{level}", "file:///logs//error"},
+		{"file:///logs/{date}/{level}", "file:///logs/2024-01-02/"},
+		{"file://{+path}", "file://"},
+		{"docs://{#section}", "docs://#"},
+		{"repo://{owner}{/repo}", "repo://golang/"},
+	}
+	for _, tt := range misses {
+		_, ok := MustParse(tt.template).Match(tt.uri)
+		assert.False(t, ok, "%s should not match %s", tt.template, tt.uri)
+	}
+}
+
+func TestTemplate_RoundTrip(t *testing.T) {
+	tmpl := MustParse("file:///logs/{date}/{level}{?tail}")
+	vars := map[string]string{"date": "2024-01-02", "level": "a/b c", "tail": "100"}
+
+	uri := tmpl.Expand(vars)
+	assert.Equal(t, "file:///logs/2024-01-02/a%2Fb%20c?tail=100", uri)
+	matched, ok := tmpl.Match(uri)
+	require.True(t, ok)
+	assert.Equal(t, vars, matched)
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, template := range []string{
+		"file:///{",
+		"file:///}",
+		"file:///{}",
+		"file:///{var*}",
+		"file:///{var:3}",
+		"file:///{=var}",
+		"file:///{a b}",
+		"file:///{a}/{a}",
+	} {
+		_, err := Parse(template)
+		assert.Error(t, err, template)
+	}
+}
+
+func TestTemplate_Specificity(t *testing.T) {
+	assert.Equal(t, len("file:///logs/"), MustParse("file:///logs/{date}").Specificity())
+	assert.Greater(t, MustParse("file:///logs/{date}/error").Specificity(), MustParse("file:///logs/{date}/{level}").Specificity())
+	assert.Equal(t, []string{"date", "level"}, MustParse("file:///logs/{date}/{level}").Names())
+}
@@ -3,115 +3,24 @@ package integration
 import (
 	"context"
 	"encoding/json"
-	"net/http"
+	"fmt"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/harriteja/mcp-go-sdk/pkg/client"
+	"github.com/harriteja/mcp-go-sdk/pkg/keepalive"
+	"github.com/harriteja/mcp-go-sdk/pkg/metrics"
 	"github.com/harriteja/mcp-go-sdk/pkg/server"
-	"github.com/harriteja/mcp-go-sdk/pkg/server/transport/response"
+	"github.com/harriteja/mcp-go-sdk/pkg/server/transport"
 	"github.com/harriteja/mcp-go-sdk/pkg/types"
 )
 
-// serverHandler implements http.Handler for the MCP server
-type serverHandler struct {
-	srv *server.Server
-}
-
-func (h *serverHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	// Normalize path by trimming trailing slash and removing version prefix
-	path := strings.TrimSuffix(r.URL.Path, "/")
-	path = strings.TrimPrefix(path, "/v1")
-
-	// Check method
-	if r.Method != http.MethodPost {
-		response.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
-		return
-	}
-
-	// Handle request based on path
-	switch path {
-	case "/initialize":
-		var req types.InitializeRequest
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			response.WriteError(w, http.StatusBadRequest, err.Error())
-			return
-		}
-		resp, err := h.srv.Initialize(r.Context(), &req)
-		if err != nil {
-			if mcpErr, ok := err.(*types.Error); ok {
-				response.WriteError(w, mcpErr.Code, mcpErr.Message)
-				return
-			}
-			response.WriteError(w, http.StatusInternalServerError, err.Error())
-			return
-		}
-		response.WriteJSON(w, http.StatusOK, resp)
-
-	case "/listTools":
-		tools, err := h.srv.ListTools(r.Context())
-		if err != nil {
-			if mcpErr, ok := err.(*types.Error); ok {
-				response.WriteError(w, mcpErr.Code, mcpErr.Message)
-				return
-			}
-			response.WriteError(w, http.StatusInternalServerError, err.Error())
-			return
-		}
-		response.WriteJSON(w, http.StatusOK, tools)
-
-	case "/callTool":
-		var req struct {
-			Name string                 `json:"name"`
-			Args map[string]interface{} `json:"args"`
-		}
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			response.WriteError(w, http.StatusBadRequest, err.Error())
-			return
-		}
-
-		// Validate tool name
-		tools, err := h.srv.ListTools(r.Context())
-		if err != nil {
-			if mcpErr, ok := err.(*types.Error); ok {
-				response.WriteError(w, mcpErr.Code, mcpErr.Message)
-				return
-			}
-			response.WriteError(w, http.StatusInternalServerError, err.Error())
-			return
-		}
-		toolExists := false
-		for _, t := range tools {
-			if t.Name == req.Name {
-				toolExists = true
-				break
-			}
-		}
-		if !toolExists {
-			response.WriteError(w, http.StatusNotFound, "Tool not found")
-			return
-		}
-
-		result, err := h.srv.CallTool(r.Context(), req.Name, req.Args)
-		if err != nil {
-			if mcpErr, ok := err.(*types.Error); ok {
-				response.WriteError(w, mcpErr.Code, mcpErr.Message)
-				return
-			}
-			response.WriteError(w, http.StatusInternalServerError, err.Error())
-			return
-		}
-		response.WriteJSON(w, http.StatusOK, result)
-
-	default:
-		response.WriteError(w, http.StatusNotFound, "endpoint not found")
-	}
-}
-
 func TestServerIntegration(t *testing.T) {
 	// Create server and register handlers
 	srv, err := server.New(&server.Options{
@@ -151,7 +60,7 @@ func TestServerIntegration(t *testing.T) {
 	})
 
 	// Create server handler
-	handler := &serverHandler{srv: srv}
+	handler := transport.NewHTTPTransport(srv, types.NewNoOpLogger()).Handler()
 
 	// Create test HTTP server
 	ts := httptest.NewServer(handler)
@@ -190,7 +99,8 @@ func TestServerIntegration(t *testing.T) {
 			"input": "test",
 		})
 		require.NoError(t, err)
-		assert.Equal(t, "test success", result)
+		require.Len(t, result.Content, 1)
+		assert.Equal(t, types.NewTextContent("test success"), result.Content[0])
 	})
 
 	// Test call tool with invalid parameters
@@ -226,7 +136,7 @@ func TestServerWithErrors(t *testing.T) {
 	})
 
 	// Create server handler
-	handler := &serverHandler{srv: srv}
+	handler := transport.NewHTTPTransport(srv, types.NewNoOpLogger()).Handler()
 
 	// Create test HTTP server
 	ts := httptest.NewServer(handler)
@@ -241,6 +151,8 @@ func TestServerWithErrors(t *testing.T) {
 		},
 	})
 
+	require.NoError(t, cli.Initialize(context.Background()))
+
 	// Test error handling
 	t.Run("ErrorHandling", func(t *testing.T) {
 		// First three requests should succeed
@@ -255,3 +167,617 @@ func TestServerWithErrors(t *testing.T) {
 		assert.Equal(t, "failed to list tools: MCP error 500: Internal server error", err.Error())
 	})
 }
+
+type weather struct {
+	City        string  `json:"city"`
+	Temperature float64 `json:"temperature"`
+}
+
+func TestToolOutputSchemas(t *testing.T) {
+	// Validation is left to the client to check it catches bad results
+	srv, err := server.New(&server.Options{
+		Name:             "test-server",
+		Version:          "1.0.0",
+		Logger:           types.NewNoOpLogger(),
+		OutputValidation: server.OutputValidationOff,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, srv.AddTool(server.NewTypedTool("weather", "Current weather",
+		func(ctx context.Context, in struct {
+			City string `json:"city"`
+		}) (weather, error) {
+			return weather{City: in.City, Temperature: 21.5}, nil
+		})))
+
+	tool, _ := server.NewTypedTool("broken", "Returns the wrong shape", func(ctx context.Context, in struct{}) (weather, error) {
+		return weather{}, nil
+	})
+	require.NoError(t, srv.AddTool(tool, func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return &types.CallToolResult{StructuredContent: map[string]interface{}{"city": 42}}, nil
+	}))
+
+	ts := httptest.NewServer(transport.NewHTTPTransport(srv, types.NewNoOpLogger()).Handler())
+	defer ts.Close()
+
+	cli := client.New(client.Options{ServerURL: ts.URL})
+	require.NoError(t, cli.Initialize(context.Background()))
+	_, err = cli.ListTools(context.Background())
+	require.NoError(t, err)
+
+	got, err := client.CallToolAs[weather](context.Background(), cli, "weather", map[string]interface{}{"city": "Paris"})
+	require.NoError(t, err)
+	assert.Equal(t, weather{City: "Paris", Temperature: 21.5}, got)
+
+	_, err = cli.CallTool(context.Background(), "broken", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid structured content from tool broken")
+}
+
+func TestResourcePagination(t *testing.T) {
+	srv, err := server.New(&server.Options{
+		Name:     "test-server",
+		Version:  "1.0.0",
+		Logger:   types.NewNoOpLogger(),
+		PageSize: 2,
+	})
+	require.NoError(t, err)
+
+	var resources []types.Resource
+	for i := 0; i < 5; i++ {
+		resources = append(resources, types.Resource{URI: fmt.Sprintf("file:///%d", i), Name: fmt.Sprint(i)})
+	}
+	srv.OnListResources(func(ctx context.Context) ([]types.Resource, error) {
+		return resources, nil
+	})
+
+	ts := httptest.NewServer(transport.NewHTTPTransport(srv, types.NewNoOpLogger()).Handler())
+	defer ts.Close()
+
+	cli := client.New(client.Options{ServerURL: ts.URL})
+	require.NoError(t, cli.Initialize(context.Background()))
+
+	page, next, err := cli.ListResourcesPage(context.Background(), "")
+	require.NoError(t, err)
+	assert.Equal(t, resources[:2], page)
+	assert.NotEmpty(t, next)
+
+	var got []types.Resource
+	for resource, err := range cli.ResourcesIter(context.Background()) {
+		require.NoError(t, err)
+		got = append(got, resource)
+	}
+	assert.Equal(t, resources, got)
+
+	all, err := cli.ListResources(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, resources, all)
+}
+
+func TestResourceTemplates(t *testing.T) {
+	srv, err := server.New(&server.Options{
+		Name:    "test-server",
+		Version: "1.0.0",
+		Logger:  types.NewNoOpLogger(),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, srv.AddResource(types.Resource{URI: "file:///README.md", Name: "readme", MimeType: "text/markdown"}, func(ctx context.Context, uri string) ([]byte, string, error) {
+		return []byte("# Readme"), "", nil
+	}))
+	require.NoError(t, srv.AddResourceTemplate(types.ResourceTemplate{URITemplate: "file:///logs/{date}/{level}{?tail}", Name: "logs", MimeType: "text/plain"}, func(ctx context.Context, uri string, vars map[string]string) ([]byte, string, error) {
+		return []byte(fmt.Sprintf("%s %s %s", vars["date"], vars["level"], vars["tail"])), "", nil
+	}))
+
+	ts := httptest.NewServer(transport.NewHTTPTransport(srv, types.NewNoOpLogger()).Handler())
+	defer ts.Close()
+
+	cli := client.New(client.Options{ServerURL: ts.URL})
+	require.NoError(t, cli.Initialize(context.Background()))
+	assert.True(t, cli.ServerCapabilities().Resources.ListChanged)
+
+	resources, err := cli.ListResources(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []types.Resource{{URI: "file:///README.md", Name: "readme", MimeType: "text/markdown"}}, resources)
+
+	templates, err := cli.ListResourceTemplates(context.Background())
+	require.NoError(t, err)
+	require.Len(t, templates, 1)
+	assert.Equal(t, "file:///logs/{date}/{level}{?tail}", templates[0].URITemplate)
+
+	data, mimeType, err := cli.ReadResource(context.Background(), "file:///logs/2024-01-02/error?tail=10")
+	require.NoError(t, err)
+	assert.Equal(t, "2024-01-02 error 10", string(data))
+	assert.Equal(t, "text/plain", mimeType)
+
+	_, _, err = cli.ReadResource(context.Background(), "file:///other")
+	assert.Error(t, err)
+}
+
+func TestHTTPNotifications(t *testing.T) {
+	srv, err := server.New(&server.Options{
+		Name:    "test-server",
+		Version: "1.0.0",
+		Logger:  types.NewNoOpLogger(),
+	})
+	require.NoError(t, err)
+
+	ts := httptest.NewServer(transport.NewHTTPTransport(srv, types.NewNoOpLogger()).Handler())
+	defer ts.Close()
+
+	cli := client.New(client.Options{ServerURL: ts.URL})
+	received := make(chan struct{}, 16)
+	cli.OnNotification(types.NotificationToolsListChanged, func(ctx context.Context, params json.RawMessage) {
+		received <- struct{}{}
+	})
+	require.NoError(t, cli.Initialize(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	listening := make(chan error, 1)
+	go func() {
+		listening <- cli.Listen(ctx)
+	}()
+
+	// Notifications sent before the stream is open are dropped, so keep
+	// changing the tools until one arrives
+	n := 0
+	require.Eventually(t, func() bool {
+		n++
+		require.NoError(t, srv.AddTool(types.Tool{Name: fmt.Sprintf("tool%d", n)}, func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			return nil, nil
+		}))
+		select {
+		case <-received:
+			return true
+		case <-time.After(10 * time.Millisecond):
+			return false
+		}
+	}, 2*time.Second, 10*time.Millisecond)
+
+	cancel()
+	assert.ErrorIs(t, <-listening, context.Canceled)
+}
+
+func TestToolProgress(t *testing.T) {
+	srv, err := server.New(&server.Options{
+		Name:    "test-server",
+		Version: "1.0.0",
+		Logger:  types.NewNoOpLogger(),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, srv.AddTool(types.Tool{Name: "count"}, func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		for i := 1; i <= 3; i++ {
+			if err := server.ReportProgress(ctx, float64(i), 3, fmt.Sprintf("step %d", i)); err != nil {
+				return nil, err
+			}
+		}
+		return "counted", nil
+	}))
+
+	ts := httptest.NewServer(transport.NewHTTPTransport(srv, types.NewNoOpLogger()).Handler())
+	defer ts.Close()
+
+	cli := client.New(client.Options{ServerURL: ts.URL})
+	require.NoError(t, cli.Initialize(context.Background()))
+
+	var steps []string
+	result, err := cli.CallTool(context.Background(), "count", nil, client.OnProgress(func(progress types.ProgressNotification) {
+		assert.Equal(t, float64(3), progress.Total)
+		steps = append(steps, progress.Message)
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "counted", result.Text())
+	assert.Equal(t, []string{"step 1", "step 2", "step 3"}, steps)
+
+	// Without a handler the response is plain JSON
+	result, err = cli.CallTool(context.Background(), "count", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "counted", result.Text())
+}
+
+func TestResourceSubscriptions(t *testing.T) {
+	srv, err := server.New(&server.Options{
+		Name:    "test-server",
+		Version: "1.0.0",
+		Logger:  types.NewNoOpLogger(),
+	})
+	require.NoError(t, err)
+	srv.OnReadResource(func(ctx context.Context, uri string) ([]byte, string, error) {
+		return []byte("hello"), "text/plain", nil
+	})
+
+	ts := httptest.NewServer(transport.NewHTTPTransport(srv, types.NewNoOpLogger()).Handler())
+	defer ts.Close()
+
+	cli := client.New(client.Options{ServerURL: ts.URL})
+	require.NoError(t, cli.Initialize(context.Background()))
+	assert.True(t, cli.ServerCapabilities().Resources.Subscribe)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = cli.Listen(ctx)
+	}()
+
+	updated := make(chan string, 16)
+	require.NoError(t, cli.Subscribe(context.Background(), "file:///greeting", func(uri string) {
+		updated <- uri
+	}))
+
+	// Updates sent before the stream is open are dropped
+	require.Eventually(t, func() bool {
+		srv.NotifyResourceUpdated("file:///greeting")
+		select {
+		case uri := <-updated:
+			return assert.Equal(t, "file:///greeting", uri)
+		case <-time.After(10 * time.Millisecond):
+			return false
+		}
+	}, 2*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, cli.Unsubscribe(context.Background(), "file:///greeting"))
+}
+
+func TestSampling(t *testing.T) {
+	srv, err := server.New(&server.Options{
+		Name:    "test-server",
+		Version: "1.0.0",
+		Logger:  types.NewNoOpLogger(),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, srv.AddTool(types.Tool{Name: "ask"}, func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		session, ok := server.SessionFromContext(ctx)
+		if !ok {
+			return nil, fmt.Errorf("no session")
+		}
+		resp, err := session.CreateMessage(ctx, &types.CreateMessageRequest{
+			Messages: []types.SamplingMessage{
+				{Role: "user", Content: types.NewTextContent(args["question"].(string))},
+			},
+			MaxTokens: 100,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return resp.Content.(types.TextContent).Text, nil
+	}))
+
+	ts := httptest.NewServer(transport.NewHTTPTransport(srv, types.NewNoOpLogger()).Handler())
+	defer ts.Close()
+
+	cli := client.New(client.Options{
+		ServerURL: ts.URL,
+		SamplingHandler: func(ctx context.Context, req *types.CreateMessageRequest) (*types.CreateMessageResponse, error) {
+			question := req.Messages[0].Content.(types.TextContent).Text
+			return &types.CreateMessageResponse{
+				Role:       "assistant",
+				Content:    types.NewTextContent("answer to " + question),
+				Model:      "test-model",
+				StopReason: types.StopReasonEndTurn,
+			}, nil
+		},
+	})
+	require.NoError(t, cli.Initialize(context.Background()))
+
+	result, err := cli.CallTool(context.Background(), "ask", map[string]interface{}{"question": "why"})
+	require.NoError(t, err)
+	assert.Equal(t, "answer to why", result.Text())
+
+	// Clients without a sampling handler do not declare the capability
+	plain := client.New(client.Options{ServerURL: ts.URL})
+	require.NoError(t, plain.Initialize(context.Background()))
+	_, err = plain.CallTool(context.Background(), "ask", map[string]interface{}{"question": "why"})
+	assert.Error(t, err)
+}
+
+func TestRoots(t *testing.T) {
+	srv, err := server.New(&server.Options{
+		Name:    "test-server",
+		Version: "1.0.0",
+		Logger:  types.NewNoOpLogger(),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, srv.AddTool(types.Tool{Name: "roots"}, func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		session, ok := server.SessionFromContext(ctx)
+		if !ok {
+			return nil, fmt.Errorf("no session")
+		}
+		roots, err := session.ListRoots(ctx)
+		if err != nil {
+			return nil, err
+		}
+		uris := make([]string, 0, len(roots))
+		for _, root := range roots {
+			uris = append(uris, root.URI)
+		}
+		return strings.Join(uris, ","), nil
+	}))
+
+	ts := httptest.NewServer(transport.NewHTTPTransport(srv, types.NewNoOpLogger()).Handler())
+	defer ts.Close()
+
+	cli := client.New(client.Options{
+		ServerURL: ts.URL,
+		Roots:     client.StaticRoots(types.Root{URI: "file:///project", Name: "project"}),
+	})
+	require.NoError(t, cli.Initialize(context.Background()))
+
+	result, err := cli.CallTool(context.Background(), "roots", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "file:///project", result.Text())
+
+	// Changing the roots invalidates those the server cached
+	require.NoError(t, cli.SetRoots(context.Background(), types.Root{URI: "file:///a"}, types.Root{URI: "file:///b"}))
+	result, err = cli.CallTool(context.Background(), "roots", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "file:///a,file:///b", result.Text())
+}
+
+func TestElicitation(t *testing.T) {
+	srv, err := server.New(&server.Options{
+		Name:    "test-server",
+		Version: "1.0.0",
+		Logger:  types.NewNoOpLogger(),
+	})
+	require.NoError(t, err)
+
+	schema := json.RawMessage(`{"type":"object","properties":{"name":{"type":"string"}},"required":["name"]}`)
+	require.NoError(t, srv.AddTool(types.Tool{Name: "greet"}, func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		session, ok := server.SessionFromContext(ctx)
+		if !ok {
+			return nil, fmt.Errorf("no session")
+		}
+		result, err := session.Elicit(ctx, "What is your name?", schema)
+		if err != nil {
+			return nil, err
+		}
+		if result.Action != types.ElicitActionAccept {
+			return "Hello, stranger", nil
+		}
+		return fmt.Sprintf("Hello, %s", result.Content["name"]), nil
+	}))
+
+	ts := httptest.NewServer(transport.NewHTTPTransport(srv, types.NewNoOpLogger()).Handler())
+	defer ts.Close()
+
+	action := types.ElicitActionAccept
+	cli := client.New(client.Options{
+		ServerURL: ts.URL,
+		ElicitationHandler: func(ctx context.Context, req *types.ElicitRequest) (*types.ElicitResult, error) {
+			assert.Equal(t, "What is your name?", req.Message)
+			if action != types.ElicitActionAccept {
+				return &types.ElicitResult{Action: action}, nil
+			}
+			return &types.ElicitResult{Action: action, Content: map[string]interface{}{"name": "Ada"}}, nil
+		},
+	})
+	require.NoError(t, cli.Initialize(context.Background()))
+
+	result, err := cli.CallTool(context.Background(), "greet", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello, Ada", result.Text())
+
+	action = types.ElicitActionDecline
+	result, err = cli.CallTool(context.Background(), "greet", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello, stranger", result.Text())
+}
+
+func TestPrompts(t *testing.T) {
+	srv, err := server.New(&server.Options{
+		Name:    "test-server",
+		Version: "1.0.0",
+		Logger:  types.NewNoOpLogger(),
+	})
+	require.NoError(t, err)
+
+	srv.OnListPrompts(func(ctx context.Context) ([]types.Prompt, error) {
+		return []types.Prompt{{
+			Name:        "review",
+			Description: "Review a file",
+			Arguments:   []types.PromptArgument{{Name: "file", Description: "File to review", Required: true}},
+		}}, nil
+	})
+	srv.OnGetPrompt(func(ctx context.Context, name string, args map[string]interface{}) (*types.GetPromptResult, error) {
+		file, _ := args["file"].(string)
+		if name != "review" || file == "" {
+			return nil, types.NewError(400, "Unknown prompt or missing file")
+		}
+		return &types.GetPromptResult{
+			Description: "Review " + file,
+			Messages: []types.PromptMessage{
+				types.NewPromptMessage(types.RoleUser, types.NewTextContent("Please review this file")),
+				types.NewPromptMessage(types.RoleUser, types.NewEmbeddedResource(types.NewResourceContents("file:///"+file, []byte("package main"), "text/plain"))),
+				types.NewPromptMessage(types.RoleAssistant, types.NewImageContent([]byte("png"), "image/png")),
+			},
+		}, nil
+	})
+
+	ts := httptest.NewServer(transport.NewHTTPTransport(srv, types.NewNoOpLogger()).Handler())
+	defer ts.Close()
+
+	cli := client.New(client.Options{ServerURL: ts.URL})
+	require.NoError(t, cli.Initialize(context.Background()))
+
+	prompts, err := cli.ListPrompts(context.Background())
+	require.NoError(t, err)
+	require.Len(t, prompts, 1)
+	assert.Equal(t, []types.PromptArgument{{Name: "file", Description: "File to review", Required: true}}, prompts[0].Arguments)
+
+	result, err := cli.GetPrompt(context.Background(), "review", map[string]interface{}{"file": "main.go"})
+	require.NoError(t, err)
+	assert.Equal(t, "Review main.go", result.Description)
+	require.Len(t, result.Messages, 3)
+	assert.Equal(t, types.NewTextContent("Please review this file"), result.Messages[0].Content)
+	resource, ok := result.Messages[1].Content.(types.EmbeddedResource)
+	require.True(t, ok)
+	assert.Equal(t, "package main", resource.Resource.Text)
+	assert.Equal(t, types.RoleAssistant, result.Messages[2].Role)
+	assert.IsType(t, types.ImageContent{}, result.Messages[2].Content)
+
+	_, err = cli.GetPrompt(context.Background(), "review", nil)
+	assert.Error(t, err)
+}
+
+func TestCompletion(t *testing.T) {
+	srv, err := server.New(&server.Options{
+		Name:    "test-server",
+		Version: "1.0.0",
+		Logger:  types.NewNoOpLogger(),
+	})
+	require.NoError(t, err)
+
+	srv.AddResourceCompleter("repo://{owner}/{name}", "name", func(ctx context.Context, value string, arguments map[string]string) ([]string, error) {
+		repos := map[string][]string{"golang": {"go", "tools"}}
+		var values []string
+		for _, repo := range repos[arguments["owner"]] {
+			if strings.HasPrefix(repo, value) {
+				values = append(values, repo)
+			}
+		}
+		return values, nil
+	})
+
+	ts := httptest.NewServer(transport.NewHTTPTransport(srv, types.NewNoOpLogger()).Handler())
+	defer ts.Close()
+
+	cli := client.New(client.Options{ServerURL: ts.URL})
+	require.NoError(t, cli.Initialize(context.Background()))
+	require.NotNil(t, cli.ServerCapabilities().Completions)
+
+	completion, err := cli.Complete(context.Background(), types.ResourceReference("repo://{owner}/{name}"), "name", "t", map[string]string{"owner": "golang"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tools"}, completion.Values)
+	assert.False(t, completion.HasMore)
+}
+
+func TestLogging(t *testing.T) {
+	srv, err := server.New(&server.Options{
+		Name:    "test-server",
+		Version: "1.0.0",
+		Logger:  types.NewNoOpLogger(),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, srv.AddTool(types.Tool{Name: "work"}, func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		if err := server.Log(ctx, types.LoggingLevelDebug, "work", "details"); err != nil {
+			return nil, err
+		}
+		if err := server.Log(ctx, types.LoggingLevelWarning, "work", "disk almost full"); err != nil {
+			return nil, err
+		}
+		return "done", nil
+	}))
+
+	ts := httptest.NewServer(transport.NewHTTPTransport(srv, types.NewNoOpLogger()).Handler())
+	defer ts.Close()
+
+	cli := client.New(client.Options{ServerURL: ts.URL})
+	require.NoError(t, cli.Initialize(context.Background()))
+	require.NotNil(t, cli.ServerCapabilities().Logging)
+
+	var messages []types.LoggingMessageNotification
+	cli.OnLogMessage(func(ctx context.Context, msg types.LoggingMessageNotification) {
+		messages = append(messages, msg)
+	})
+	require.NoError(t, cli.SetLogLevel(context.Background(), types.LoggingLevelInfo))
+
+	result, err := cli.CallTool(context.Background(), "work", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "done", result.Text())
+	assert.Equal(t, []types.LoggingMessageNotification{
+		{Level: types.LoggingLevelWarning, Logger: "work", Data: "disk almost full"},
+	}, messages)
+}
+
+func TestKeepAlive(t *testing.T) {
+	srv, err := server.New(&server.Options{
+		Name:      "test-server",
+		Version:   "1.0.0",
+		Logger:    types.NewNoOpLogger(),
+		KeepAlive: keepalive.Options{Interval: 10 * time.Millisecond, MaxMissed: 2},
+	})
+	require.NoError(t, err)
+
+	ts := httptest.NewServer(transport.NewHTTPTransport(srv, types.NewNoOpLogger()).Handler())
+	defer ts.Close()
+
+	lost := make(chan error, 1)
+	cli := client.New(client.Options{
+		ServerURL: ts.URL,
+		KeepAlive: keepalive.Options{Interval: 10 * time.Millisecond, MaxMissed: 2},
+		OnConnectionLost: func(err error) {
+			lost <- err
+		},
+	})
+	require.NoError(t, cli.Initialize(context.Background()))
+	defer cli.Close()
+
+	rtt, err := cli.Ping(context.Background())
+	require.NoError(t, err)
+	assert.Greater(t, rtt, time.Duration(0))
+
+	// The client answers the pings of the server on its event stream
+	ctx, cancel := context.WithCancel(context.Background())
+	listening := make(chan struct{})
+	go func() {
+		defer close(listening)
+		_ = cli.Listen(ctx)
+	}()
+	time.Sleep(100 * time.Millisecond)
+	assert.Len(t, srv.Sessions(), 1)
+	cancel()
+	<-listening
+
+	// The client notices the server is gone
+	ts.Close()
+	select {
+	case err := <-lost:
+		assert.Contains(t, err.Error(), "peer missed 2 pings")
+	case <-time.After(2 * time.Second):
+		t.Fatal("lost connection not detected")
+	}
+}
+
+func TestKeepAliveMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	collector := metrics.NewPrometheusCollector(registry)
+
+	// The server and the client share the collector
+	srv, err := server.New(&server.Options{
+		Name:      "test-server",
+		Version:   "1.0.0",
+		Logger:    types.NewNoOpLogger(),
+		KeepAlive: keepalive.Options{Interval: 10 * time.Millisecond, Metrics: collector},
+	})
+	require.NoError(t, err)
+
+	ts := httptest.NewServer(transport.NewHTTPTransport(srv, types.NewNoOpLogger()).Handler())
+	defer ts.Close()
+
+	cli := client.New(client.Options{
+		ServerURL: ts.URL,
+		KeepAlive: keepalive.Options{Interval: 10 * time.Millisecond, Metrics: collector},
+	})
+	defer cli.Close()
+
+	// Initializing again, as clients do when they reconnect, reuses the
+	// registered metric
+	require.NoError(t, cli.Initialize(context.Background()))
+	require.NoError(t, cli.Initialize(context.Background()))
+
+	// The pings of the client are observed
+	assert.Eventually(t, func() bool {
+		families, err := registry.Gather()
+		require.NoError(t, err)
+		for _, family := range families {
+			if family.GetName() == "ping_rtt_seconds" {
+				return family.GetMetric()[0].GetHistogram().GetSampleCount() > 0
+			}
+		}
+		return false
+	}, 2*time.Second, 10*time.Millisecond)
+}